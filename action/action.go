@@ -1,14 +1,19 @@
 package action
 
 import (
+	"bytes"
+
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/dominodatalab/controller-util/collection"
 	"github.com/dominodatalab/controller-util/core"
 )
 
@@ -18,7 +23,16 @@ func CreateOwnedResource(ctx *core.Context, owner metav1.Object, controlled clie
 }
 
 func CreateOrUpdateOwnedResource(ctx *core.Context, owner metav1.Object, controlled client.Object) error {
-	found, gvk, err := createOwnedResource(ctx, owner, controlled)
+	if err := ctrl.SetControllerReference(owner, controlled, ctx.Scheme); err != nil {
+		return err
+	}
+	return CreateOrUpdateResource(ctx, controlled)
+}
+
+// CreateOrUpdateResource behaves like CreateOrUpdateOwnedResource without setting a controller reference, e.g. for
+// cluster-scoped objects managed on behalf of a namespaced reconcile object.
+func CreateOrUpdateResource(ctx *core.Context, controlled client.Object) error {
+	found, gvk, err := createResource(ctx, controlled)
 	if err != nil {
 		return err
 	}
@@ -36,6 +50,17 @@ func CreateOrUpdateOwnedResource(ctx *core.Context, owner metav1.Object, control
 		return err
 	}
 
+	// the object drifted when it differs although the desired state is the one applied last time
+	lastApplied, err := ctx.Patch.Annotator.GetOriginalConfiguration(found)
+	if err != nil {
+		return err
+	}
+	desired, err := ctx.Patch.Annotator.GetOriginalConfiguration(controlled)
+	if err != nil {
+		return err
+	}
+	drifted := lastApplied != nil && bytes.Equal(lastApplied, desired)
+
 	controlled.SetResourceVersion(found.GetResourceVersion())
 
 	// ensure we do not modify "generated" values for certain resources
@@ -46,10 +71,78 @@ func CreateOrUpdateOwnedResource(ctx *core.Context, owner metav1.Object, control
 	case *batchv1.Job:
 		current := found.(*batchv1.Job)
 		modified.Spec.Selector = current.Spec.Selector
+	case *appsv1.Deployment:
+		// fields left unset are scaled by autoscalers or defaulted by the server, keep them
+		current := found.(*appsv1.Deployment)
+		if modified.Spec.Replicas == nil {
+			modified.Spec.Replicas = current.Spec.Replicas
+		}
+		if modified.Spec.RevisionHistoryLimit == nil {
+			modified.Spec.RevisionHistoryLimit = current.Spec.RevisionHistoryLimit
+		}
+		if modified.Spec.ProgressDeadlineSeconds == nil {
+			modified.Spec.ProgressDeadlineSeconds = current.Spec.ProgressDeadlineSeconds
+		}
+		if modified.Spec.Strategy.Type == "" {
+			modified.Spec.Strategy = current.Spec.Strategy
+		}
 	}
 
 	ctx.Log.V(1).Info("Updating controlled object", "gvk", gvk, "object", controlled)
-	return ctx.Client.Update(ctx, controlled)
+	if err = ctx.Client.Update(ctx, controlled); err != nil {
+		return err
+	}
+	ctx.Forget(controlled)
+	if drifted {
+		ctx.DriftCorrected(controlled)
+	}
+
+	return nil
+}
+
+// ApplyAll creates or updates each desired object, owned by the reconcile object and labeled with matchLabels, then
+// deletes objects of the owned list type that carry matchLabels and are controlled by the reconcile object but are not
+// part of the desired set.
+func ApplyAll(ctx *core.Context, desired []client.Object, owned client.ObjectList, matchLabels map[string]string) error {
+	keep := map[client.ObjectKey]struct{}{}
+	for _, obj := range desired {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		obj.SetLabels(collection.MergeStringMaps(matchLabels, labels))
+
+		if err := CreateOrUpdateOwnedResource(ctx, ctx.Object, obj); err != nil {
+			return err
+		}
+		keep[client.ObjectKeyFromObject(obj)] = struct{}{}
+	}
+
+	listOpts := []client.ListOption{
+		client.InNamespace(ctx.Object.GetNamespace()),
+		client.MatchingLabels(matchLabels),
+	}
+	if err := ctx.Client.List(ctx, owned, listOpts...); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(owned)
+	if err != nil {
+		return err
+	}
+
+	var stale []client.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || !metav1.IsControlledBy(obj, ctx.Object) {
+			continue
+		}
+		if _, ok = keep[client.ObjectKeyFromObject(obj)]; !ok {
+			stale = append(stale, obj)
+		}
+	}
+
+	return DeleteIfExists(ctx, stale...)
 }
 
 func DeleteIfExists(ctx *core.Context, objs ...client.Object) error {
@@ -66,6 +159,7 @@ func DeleteIfExists(ctx *core.Context, objs ...client.Object) error {
 		if err := ctx.Client.Delete(ctx, obj); err != nil {
 			return err
 		}
+		ctx.Forget(obj)
 	}
 
 	return nil
@@ -90,6 +184,7 @@ func DeleteStorage(ctx *core.Context, opts []client.ListOption) error {
 			ctx.Log.Error(err, "Cannot delete persistent volume claim", "claim", key)
 			return err
 		}
+		ctx.Forget(pvc)
 	}
 
 	return nil
@@ -99,7 +194,10 @@ func createOwnedResource(ctx *core.Context, owner metav1.Object, controlled clie
 	if err = ctrl.SetControllerReference(owner, controlled, ctx.Scheme); err != nil {
 		return
 	}
+	return createResource(ctx, controlled)
+}
 
+func createResource(ctx *core.Context, controlled client.Object) (found client.Object, gvk schema.GroupVersionKind, err error) {
 	var gvks []schema.GroupVersionKind
 	if gvks, _, err = ctx.Scheme.ObjectKinds(controlled); err != nil {
 		return
@@ -117,7 +215,11 @@ func createOwnedResource(ctx *core.Context, owner metav1.Object, controlled clie
 		}
 
 		ctx.Log.V(1).Info("Creating controlled object", "gvk", gvk, "object", controlled)
-		err = ctx.Client.Create(ctx, controlled)
+		if err = ctx.Client.Create(ctx, controlled); err == nil {
+			ctx.Forget(controlled)
+			// the created object is the current state, callers must not update it again
+			found = controlled.DeepCopyObject().(client.Object)
+		}
 	}
 
 	return
@@ -0,0 +1,150 @@
+package action
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+// newTestContext returns a reconcile context for owner backed by a fake client holding objs.
+func newTestContext(owner client.Object, objs ...client.Object) *core.Context {
+	cl := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(append([]client.Object{owner}, objs...)...).
+		Build()
+
+	return &core.Context{
+		Context:    context.Background(),
+		Log:        logr.Discard(),
+		Data:       core.ContextData{},
+		Patch:      core.NewPatch(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}),
+		Object:     owner,
+		Client:     cl,
+		Scheme:     clientgoscheme.Scheme,
+		Recorder:   record.NewFakeRecorder(100),
+		Conditions: core.NewConditionHelper(owner),
+	}
+}
+
+func TestApplyAll(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	matchLabels := map[string]string{"example.com/set": "workers"}
+
+	secret := func(name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", Labels: labels}}
+	}
+	stale := secret("stale", matchLabels)
+	if err := controllerutil.SetControllerReference(owner, stale, clientgoscheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	uncontrolled := secret("uncontrolled", matchLabels)
+	unlabeled := secret("unlabeled", nil)
+	if err := controllerutil.SetControllerReference(owner, unlabeled, clientgoscheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	ctx := newTestContext(owner, stale, uncontrolled, unlabeled)
+
+	desired := []client.Object{
+		secret("first", map[string]string{"example.com/extra": "true"}),
+		secret("second", nil),
+	}
+	if err := ApplyAll(ctx, desired, &corev1.SecretList{}, matchLabels); err != nil {
+		t.Fatalf("ApplyAll() error = %v", err)
+	}
+
+	for _, name := range []string{"first", "second"} {
+		found := &corev1.Secret{}
+		if err := ctx.Client.Get(ctx, client.ObjectKey{Namespace: "team", Name: name}, found); err != nil {
+			t.Fatalf("desired object %s not applied: %v", name, err)
+		}
+		if found.Labels["example.com/set"] != "workers" {
+			t.Errorf("%s labels = %v, want the match labels", name, found.Labels)
+		}
+		if !metav1.IsControlledBy(found, owner) {
+			t.Errorf("%s is not controlled by the owner", name)
+		}
+	}
+
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("stale controlled object not deleted: %v", err)
+	}
+	for _, obj := range []client.Object{uncontrolled, unlabeled} {
+		if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(obj), &corev1.Secret{}); err != nil {
+			t.Errorf("object %s outside the owned set was deleted: %v", obj.GetName(), err)
+		}
+	}
+}
+
+func TestCreateOrUpdateResourceDriftCorrected(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+	recorder := ctx.Recorder.(*record.FakeRecorder)
+	key := client.ObjectKey{Namespace: "team", Name: "child"}
+
+	desired := func(value string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			StringData: map[string]string{"key": value},
+		}
+	}
+	tamper := func() {
+		live := &corev1.Secret{}
+		if err := ctx.Client.Get(ctx, key, live); err != nil {
+			t.Fatalf("cannot get object: %v", err)
+		}
+		live.StringData = map[string]string{"key": "tampered"}
+		if err := ctx.Client.Update(ctx, live); err != nil {
+			t.Fatalf("cannot change object out of band: %v", err)
+		}
+	}
+
+	steps := []struct {
+		name   string
+		before func()
+		value  string
+		want   []string
+	}{
+		{name: "create", value: "original"},
+		{name: "unchanged", value: "original"},
+		{
+			name:   "out-of-band change",
+			before: tamper,
+			value:  "original",
+			want:   []string{"Normal DriftCorrected Reverted out-of-band changes to Secret team/child"},
+		},
+		{name: "desired change", value: "updated"},
+		{name: "out-of-band change to the new state", before: tamper, value: "updated", want: []string{
+			"Normal DriftCorrected Reverted out-of-band changes to Secret team/child",
+		}},
+	}
+
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		if err := CreateOrUpdateOwnedResource(ctx, owner, desired(step.value)); err != nil {
+			t.Fatalf("%s: CreateOrUpdateOwnedResource() error = %v", step.name, err)
+		}
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		if !reflect.DeepEqual(events, step.want) {
+			t.Errorf("%s: events = %q, want %q", step.name, events, step.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetJSONAnnotation stores the JSON encoding of v in the annotation key of obj.
+func SetJSONAnnotation(obj client.Object, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode annotation %s: %w", key, err)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = string(data)
+	obj.SetAnnotations(annotations)
+
+	return nil
+}
+
+// GetJSONAnnotation decodes the JSON stored in the annotation key of obj into out. It returns false when the
+// annotation is absent.
+func GetJSONAnnotation(obj client.Object, key string, out interface{}) (bool, error) {
+	value, ok := obj.GetAnnotations()[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(value), out); err != nil {
+		return true, fmt.Errorf("cannot decode annotation %s: %w", key, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package collection
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type annotationValue struct {
+	Replicas int      `json:"replicas"`
+	Hosts    []string `json:"hosts"`
+}
+
+func TestJSONAnnotation(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:        "obj",
+		Annotations: map[string]string{"example.com/other": "kept"},
+	}}
+	in := annotationValue{Replicas: 3, Hosts: []string{"a", "b"}}
+
+	if err := SetJSONAnnotation(obj, "example.com/config", in); err != nil {
+		t.Fatalf("SetJSONAnnotation() error = %v", err)
+	}
+	if got := obj.Annotations["example.com/config"]; got != `{"replicas":3,"hosts":["a","b"]}` {
+		t.Errorf("annotation = %s, want the JSON encoding", got)
+	}
+	if obj.Annotations["example.com/other"] != "kept" {
+		t.Errorf("annotations = %v, want other annotations kept", obj.Annotations)
+	}
+
+	var out annotationValue
+	found, err := GetJSONAnnotation(obj, "example.com/config", &out)
+	if err != nil || !found {
+		t.Fatalf("GetJSONAnnotation() = %t, %v, want found", found, err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("GetJSONAnnotation() decoded %+v, want %+v", out, in)
+	}
+
+	if found, err = GetJSONAnnotation(obj, "example.com/missing", &out); err != nil || found {
+		t.Errorf("GetJSONAnnotation() = %t, %v for a missing annotation, want not found", found, err)
+	}
+
+	obj.Annotations["example.com/config"] = "{invalid"
+	if found, err = GetJSONAnnotation(obj, "example.com/config", &out); err == nil || !found {
+		t.Errorf("GetJSONAnnotation() = %t, %v for invalid JSON, want found with an error", found, err)
+	}
+
+	if err = SetJSONAnnotation(&corev1.ConfigMap{}, "example.com/config", func() {}); err == nil {
+		t.Error("SetJSONAnnotation() accepted a value that cannot be encoded")
+	}
+}
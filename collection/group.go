@@ -0,0 +1,11 @@
+package collection
+
+// GroupBy groups items by the key derived from each item, preserving input order within each group.
+func GroupBy[T any, K comparable](items []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, item := range items {
+		k := key(item)
+		groups[k] = append(groups[k], item)
+	}
+	return groups
+}
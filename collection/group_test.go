@@ -0,0 +1,40 @@
+package collection
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	zone := func(node string) string {
+		return node[:strings.Index(node, "-")]
+	}
+
+	cases := []struct {
+		name  string
+		items []string
+		want  map[string][]string
+	}{
+		{name: "nil", items: nil, want: map[string][]string{}},
+		{name: "empty", items: []string{}, want: map[string][]string{}},
+		{
+			name:  "distinct keys",
+			items: []string{"a-1", "b-1"},
+			want:  map[string][]string{"a": {"a-1"}, "b": {"b-1"}},
+		},
+		{
+			name:  "colliding keys keep input order",
+			items: []string{"a-2", "b-1", "a-1", "a-3"},
+			want:  map[string][]string{"a": {"a-2", "a-1", "a-3"}, "b": {"b-1"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := GroupBy(tc.items, zone); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GroupBy() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
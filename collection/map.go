@@ -7,3 +7,48 @@ func MergeStringMaps(src, dst map[string]string) map[string]string {
 	}
 	return dst
 }
+
+// Merge behaves like MergeStringMaps but skips all writes when dst already contains every k/v pair in src.
+func Merge(src, dst map[string]string) map[string]string {
+	if containsAll(dst, src) {
+		return dst
+	}
+	return MergeStringMaps(src, dst)
+}
+
+func containsAll(m, sub map[string]string) bool {
+	if len(sub) > len(m) {
+		return false
+	}
+	for k, v := range sub {
+		if cur, ok := m[k]; !ok || cur != v {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeStringMapsWithConflict merges k/v pairs from each of the srcs maps into dst, in order. When a key already exists
+// in dst with a different value, onConflict decides the value to keep; a non-nil error aborts the merge. A nil dst is
+// allocated.
+func MergeStringMapsWithConflict(dst map[string]string, onConflict func(key, existing, incoming string) (string, error), srcs ...map[string]string) (map[string]string, error) {
+	if dst == nil {
+		dst = map[string]string{}
+	}
+	for _, src := range srcs {
+		for k, v := range src {
+			existing, ok := dst[k]
+			if !ok || existing == v {
+				dst[k] = v
+				continue
+			}
+
+			resolved, err := onConflict(k, existing, v)
+			if err != nil {
+				return dst, err
+			}
+			dst[k] = resolved
+		}
+	}
+	return dst, nil
+}
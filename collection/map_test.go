@@ -0,0 +1,141 @@
+package collection
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMergeMatchesMergeStringMaps(t *testing.T) {
+	cases := []struct {
+		name string
+		src  map[string]string
+		dst  map[string]string
+	}{
+		{name: "empty src", src: map[string]string{}, dst: map[string]string{"a": "1"}},
+		{name: "nil src", src: nil, dst: map[string]string{"a": "1"}},
+		{name: "empty dst", src: map[string]string{"a": "1"}, dst: map[string]string{}},
+		{name: "subset", src: map[string]string{"a": "1"}, dst: map[string]string{"a": "1", "b": "2"}},
+		{name: "equal", src: map[string]string{"a": "1", "b": "2"}, dst: map[string]string{"a": "1", "b": "2"}},
+		{name: "new key", src: map[string]string{"c": "3"}, dst: map[string]string{"a": "1", "b": "2"}},
+		{name: "changed value", src: map[string]string{"a": "2"}, dst: map[string]string{"a": "1", "b": "2"}},
+		{name: "empty value", src: map[string]string{"a": ""}, dst: map[string]string{"b": "2"}},
+		{name: "src larger than dst", src: map[string]string{"a": "1", "b": "2", "c": "3"}, dst: map[string]string{"a": "1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := MergeStringMaps(tc.src, copyMap(tc.dst))
+			got := Merge(tc.src, copyMap(tc.dst))
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Merge() = %v, MergeStringMaps() = %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMergeReturnsDst(t *testing.T) {
+	dst := map[string]string{"a": "1"}
+
+	got := Merge(map[string]string{"a": "1"}, dst)
+	got["b"] = "2"
+
+	if dst["b"] != "2" {
+		t.Errorf("Merge() did not return dst when it already contained src")
+	}
+}
+
+func TestMergeStringMapsWithConflict(t *testing.T) {
+	var calls []string
+	onConflict := func(key, existing, incoming string) (string, error) {
+		calls = append(calls, fmt.Sprintf("%s:%s->%s", key, existing, incoming))
+		return existing + "+" + incoming, nil
+	}
+
+	dst := map[string]string{"a": "0"}
+	got, err := MergeStringMapsWithConflict(dst, onConflict,
+		map[string]string{"a": "1", "b": "1"},
+		map[string]string{"a": "2", "b": "1"},
+	)
+	if err != nil {
+		t.Fatalf("MergeStringMapsWithConflict() error = %v", err)
+	}
+
+	if want := map[string]string{"a": "0+1+2", "b": "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeStringMapsWithConflict() = %v, want %v", got, want)
+	}
+	if want := []string{"a:0->1", "a:0+1->2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("conflict callbacks = %v, want %v in source order", calls, want)
+	}
+	if dst["a"] != "0+1+2" {
+		t.Errorf("MergeStringMapsWithConflict() did not merge into dst")
+	}
+}
+
+func TestMergeStringMapsWithConflictNilInput(t *testing.T) {
+	fail := func(key, _, _ string) (string, error) {
+		return "", fmt.Errorf("conflict on %s", key)
+	}
+
+	got, err := MergeStringMapsWithConflict(nil, fail, nil, map[string]string{"a": "1"})
+	if err != nil {
+		t.Fatalf("MergeStringMapsWithConflict() error = %v", err)
+	}
+	if want := map[string]string{"a": "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeStringMapsWithConflict() = %v, want %v", got, want)
+	}
+
+	if _, err = MergeStringMapsWithConflict(got, fail, map[string]string{"a": "2"}); err == nil {
+		t.Errorf("MergeStringMapsWithConflict() did not return the conflict error")
+	}
+
+	if got, err = MergeStringMapsWithConflict(nil, fail); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("MergeStringMapsWithConflict() without sources = %v, %v, want an empty map", got, err)
+	}
+}
+
+func BenchmarkMergeStringMaps(b *testing.B) {
+	for _, size := range []int{10, 100} {
+		src, dst := benchmarkMaps(size)
+		b.Run(fmt.Sprintf("contained/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				MergeStringMaps(src, dst)
+			}
+		})
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+	for _, size := range []int{10, 100} {
+		src, dst := benchmarkMaps(size)
+		b.Run(fmt.Sprintf("contained/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Merge(src, dst)
+			}
+		})
+	}
+}
+
+// benchmarkMaps returns a src map of size labels and a dst map holding every src label plus as many others.
+func benchmarkMaps(size int) (src, dst map[string]string) {
+	src = make(map[string]string, size)
+	dst = make(map[string]string, 2*size)
+	for i := 0; i < size; i++ {
+		src[fmt.Sprintf("app.kubernetes.io/label-%d", i)] = fmt.Sprintf("value-%d", i)
+		dst[fmt.Sprintf("app.kubernetes.io/label-%d", i)] = fmt.Sprintf("value-%d", i)
+		dst[fmt.Sprintf("example.com/other-%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	return src, dst
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,84 @@
+package collection
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// RequirementsFromMap converts k/v pairs into equality requirements, sorted by key.
+func RequirementsFromMap(m map[string]string) ([]labels.Requirement, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	reqs := make([]labels.Requirement, 0, len(keys))
+	for _, k := range keys {
+		req, err := labels.NewRequirement(k, selection.Equals, []string{m[k]})
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, *req)
+	}
+	return reqs, nil
+}
+
+// SelectorBuilder accumulates label requirements, including set-based ones. The first invalid requirement is
+// reported by Build.
+type SelectorBuilder struct {
+	reqs []labels.Requirement
+	err  error
+}
+
+func NewSelectorBuilder() *SelectorBuilder {
+	return &SelectorBuilder{}
+}
+
+func (b *SelectorBuilder) Equals(key, value string) *SelectorBuilder {
+	return b.add(key, selection.Equals, value)
+}
+
+func (b *SelectorBuilder) NotEquals(key, value string) *SelectorBuilder {
+	return b.add(key, selection.NotEquals, value)
+}
+
+func (b *SelectorBuilder) In(key string, values ...string) *SelectorBuilder {
+	return b.add(key, selection.In, values...)
+}
+
+func (b *SelectorBuilder) NotIn(key string, values ...string) *SelectorBuilder {
+	return b.add(key, selection.NotIn, values...)
+}
+
+func (b *SelectorBuilder) Exists(key string) *SelectorBuilder {
+	return b.add(key, selection.Exists)
+}
+
+func (b *SelectorBuilder) DoesNotExist(key string) *SelectorBuilder {
+	return b.add(key, selection.DoesNotExist)
+}
+
+func (b *SelectorBuilder) Build() (labels.Selector, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return labels.NewSelector().Add(b.reqs...), nil
+}
+
+func (b *SelectorBuilder) add(key string, op selection.Operator, values ...string) *SelectorBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	req, err := labels.NewRequirement(key, op, values)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.reqs = append(b.reqs, *req)
+
+	return b
+}
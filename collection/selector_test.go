@@ -0,0 +1,82 @@
+package collection
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+func TestRequirementsFromMap(t *testing.T) {
+	reqs, err := RequirementsFromMap(map[string]string{"tier": "web", "app": "api", "env": ""})
+	if err != nil {
+		t.Fatalf("RequirementsFromMap() error = %v", err)
+	}
+
+	if got := labels.NewSelector().Add(reqs...).String(); got != "app=api,env=,tier=web" {
+		t.Errorf("selector = %q, want equality requirements sorted by key", got)
+	}
+
+	if reqs, err = RequirementsFromMap(nil); err != nil || len(reqs) != 0 {
+		t.Errorf("RequirementsFromMap(nil) = %v, %v, want no requirements", reqs, err)
+	}
+	if _, err = RequirementsFromMap(map[string]string{"bad key!": "v"}); err == nil {
+		t.Error("RequirementsFromMap() accepted an invalid key")
+	}
+	if _, err = RequirementsFromMap(map[string]string{"app": "bad value!"}); err == nil {
+		t.Error("RequirementsFromMap() accepted an invalid value")
+	}
+}
+
+func TestSelectorBuilder(t *testing.T) {
+	sel, err := NewSelectorBuilder().
+		Equals("app", "api").
+		NotEquals("env", "dev").
+		In("tier", "web", "worker").
+		NotIn("zone", "a").
+		Exists("team").
+		DoesNotExist("legacy").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		labels labels.Set
+		want   bool
+	}{
+		{
+			name:   "match",
+			labels: labels.Set{"app": "api", "env": "prod", "tier": "worker", "zone": "b", "team": "data"},
+			want:   true,
+		},
+		{name: "equals", labels: labels.Set{"app": "ui", "tier": "web", "team": "data"}},
+		{name: "not equals", labels: labels.Set{"app": "api", "env": "dev", "tier": "web", "team": "data"}},
+		{name: "in", labels: labels.Set{"app": "api", "tier": "db", "team": "data"}},
+		{name: "not in", labels: labels.Set{"app": "api", "tier": "web", "zone": "a", "team": "data"}},
+		{name: "exists", labels: labels.Set{"app": "api", "tier": "web"}},
+		{name: "does not exist", labels: labels.Set{"app": "api", "tier": "web", "team": "data", "legacy": "true"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sel.Matches(tc.labels); got != tc.want {
+				t.Errorf("Matches(%v) = %t, want %t", tc.labels, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSelectorBuilderReportsFirstError(t *testing.T) {
+	_, err := NewSelectorBuilder().
+		Equals("app", "api").
+		In("tier").
+		Equals("bad key!", "v").
+		Build()
+	if err == nil {
+		t.Fatal("Build() succeeded with invalid requirements")
+	}
+	if _, wantErr := labels.NewRequirement("tier", selection.In, nil); wantErr == nil || err.Error() != wantErr.Error() {
+		t.Errorf("Build() error = %v, want the first invalid requirement %v", err, wantErr)
+	}
+}
@@ -0,0 +1,109 @@
+package components
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/action"
+	"github.com/dominodatalab/controller-util/collection"
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+const (
+	// OwnerNamespaceLabelKey links a cluster-scoped object to the namespace of the object managing it.
+	OwnerNamespaceLabelKey = "controller-util.dominodatalab.com/owner-namespace"
+	// OwnerNameLabelKey links a cluster-scoped object to the name of the object managing it.
+	OwnerNameLabelKey = "controller-util.dominodatalab.com/owner-name"
+)
+
+// ClusterResourceFunc returns the desired cluster-scoped object. Its name is set by the component.
+type ClusterResourceFunc func(*core.Context) (client.Object, error)
+
+// ClusterResource manages a single cluster-scoped object, such as a ClusterRole, on behalf of a namespaced reconcile
+// object. Cluster-scoped objects cannot be owned by namespaced ones, so the object is linked to the reconcile object
+// using owner labels and deleted by the component's finalizer instead of garbage collection. The object is named
+// "<namespace>-<instance name>" to keep it unique across namespaces, or after the instance name alone when the
+// reconcile object is cluster-scoped.
+type ClusterResource struct {
+	component metadata.AppComponent
+	kind      client.Object
+	desired   ClusterResourceFunc
+}
+
+func NewClusterResource(ac metadata.AppComponent, kind client.Object, fn ClusterResourceFunc) *ClusterResource {
+	return &ClusterResource{component: ac, kind: kind, desired: fn}
+}
+
+func (c *ClusterResource) Reconcile(ctx *core.Context) (ctrl.Result, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, fmt.Errorf("cluster resource component requires a metadata provider")
+	}
+
+	obj, err := c.desired(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	obj.SetName(c.name(ctx))
+	obj.SetNamespace("")
+	obj.SetLabels(collection.MergeStringMaps(c.linkLabels(ctx), labels))
+
+	if err = action.CreateOrUpdateResource(ctx, obj); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot apply %T %s: %w", obj, obj.GetName(), err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Finalize deletes the managed object unless it is no longer linked to the reconcile object.
+func (c *ClusterResource) Finalize(ctx *core.Context) (ctrl.Result, bool, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, false, fmt.Errorf("cluster resource component requires a metadata provider")
+	}
+
+	obj := c.kind.DeepCopyObject().(client.Object)
+	if err := ctx.Client.Get(ctx, client.ObjectKey{Name: c.name(ctx)}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, true, nil
+		}
+		return ctrl.Result{}, false, err
+	}
+
+	labels := obj.GetLabels()
+	if labels[OwnerNamespaceLabelKey] != ctx.Object.GetNamespace() || labels[OwnerNameLabelKey] != ctx.Object.GetName() {
+		ctx.Log.Info("Not deleting cluster resource linked to another object", "name", obj.GetName())
+		return ctrl.Result{}, true, nil
+	}
+
+	if err := action.DeleteIfExists(ctx, obj); err != nil {
+		return ctrl.Result{}, false, err
+	}
+
+	return ctrl.Result{}, true, nil
+}
+
+func (c *ClusterResource) name(ctx *core.Context) string {
+	name := ctx.Metadata.InstanceName(ctx.Object, c.component)
+	if ns := ctx.Object.GetNamespace(); ns != "" {
+		return fmt.Sprintf("%s-%s", ns, name)
+	}
+	return name
+}
+
+func (c *ClusterResource) linkLabels(ctx *core.Context) map[string]string {
+	return collection.MergeStringMaps(
+		map[string]string{
+			OwnerNamespaceLabelKey: ctx.Object.GetNamespace(),
+			OwnerNameLabelKey:      ctx.Object.GetName(),
+		},
+		ctx.Metadata.StandardLabels(ctx.Object, c.component, nil),
+	)
+}
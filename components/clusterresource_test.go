@@ -0,0 +1,121 @@
+package components
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+// newTestContext returns a reconcile context for owner backed by a fake client holding objs.
+func newTestContext(owner client.Object, objs ...client.Object) *core.Context {
+	cl := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(append([]client.Object{owner}, objs...)...).
+		Build()
+
+	return &core.Context{
+		Context:    context.Background(),
+		Log:        logr.Discard(),
+		Data:       core.ContextData{},
+		Patch:      core.NewPatch(schema.GroupVersionKind{Group: "test.dominodatalab.com", Version: "v1", Kind: "Owner"}),
+		Object:     owner,
+		Client:     cl,
+		Scheme:     clientgoscheme.Scheme,
+		Recorder:   record.NewFakeRecorder(100),
+		Conditions: core.NewConditionHelper(owner),
+		Metadata:   metadata.NewProvider("app"),
+	}
+}
+
+func TestClusterResource(t *testing.T) {
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+	comp := NewClusterResource("viewer", &rbacv1.ClusterRole{}, func(*core.Context) (client.Object, error) {
+		return &rbacv1.ClusterRole{Rules: rules}, nil
+	})
+
+	cases := []struct {
+		name  string
+		owner client.Object
+		want  string
+	}{
+		{
+			name:  "namespaced owner",
+			owner: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team"}},
+			want:  "team-owner-app-viewer",
+		},
+		{
+			name:  "cluster-scoped owner",
+			owner: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "owner"}},
+			want:  "owner-app-viewer",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := newTestContext(tc.owner)
+			key := client.ObjectKey{Name: tc.want}
+
+			if _, err := comp.Reconcile(ctx); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			role := &rbacv1.ClusterRole{}
+			if err := ctx.Client.Get(ctx, key, role); err != nil {
+				t.Fatalf("cluster resource %s not created: %v", tc.want, err)
+			}
+			if role.Labels[OwnerNamespaceLabelKey] != tc.owner.GetNamespace() || role.Labels[OwnerNameLabelKey] != "owner" {
+				t.Errorf("labels = %v, want links to the owner", role.Labels)
+			}
+
+			rules = append(rules, rbacv1.PolicyRule{
+				APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"},
+			})
+			if _, err := comp.Reconcile(ctx); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := ctx.Client.Get(ctx, key, role); err != nil {
+				t.Fatalf("cannot get cluster resource: %v", err)
+			}
+			if len(role.Rules) != 2 {
+				t.Errorf("rules = %v, want the updated rules", role.Rules)
+			}
+			rules = rules[:1]
+
+			if _, done, err := comp.Finalize(ctx); err != nil || !done {
+				t.Fatalf("Finalize() = %t, %v, want done", done, err)
+			}
+			if err := ctx.Client.Get(ctx, key, role); !apierrors.IsNotFound(err) {
+				t.Errorf("cluster resource not deleted on finalize: %v", err)
+			}
+		})
+	}
+}
+
+func TestClusterResourceFinalizeKeepsForeignObject(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team"}}
+	foreign := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+		Name:   "team-owner-app-viewer",
+		Labels: map[string]string{OwnerNamespaceLabelKey: "team", OwnerNameLabelKey: "other"},
+	}}
+	ctx := newTestContext(owner, foreign)
+
+	comp := NewClusterResource("viewer", &rbacv1.ClusterRole{}, nil)
+	if _, done, err := comp.Finalize(ctx); err != nil || !done {
+		t.Fatalf("Finalize() = %t, %v, want done", done, err)
+	}
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(foreign), &rbacv1.ClusterRole{}); err != nil {
+		t.Errorf("cluster resource linked to another object was deleted: %v", err)
+	}
+}
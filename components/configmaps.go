@@ -0,0 +1,83 @@
+package components
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/action"
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+// ConfigMapDataFunc returns the desired ConfigMap data keyed by ConfigMap name.
+type ConfigMapDataFunc func(*core.Context) (map[string]map[string]string, error)
+
+// ConfigMaps manages a set of ConfigMaps owned by the reconcile object. ConfigMaps that carry the component's match
+// labels and are controlled by the object but missing from the desired set are deleted. Labels are computed using the
+// metadata provider configured on the reconciler.
+type ConfigMaps struct {
+	component metadata.AppComponent
+	data      ConfigMapDataFunc
+}
+
+func NewConfigMaps(ac metadata.AppComponent, fn ConfigMapDataFunc) *ConfigMaps {
+	return &ConfigMaps{component: ac, data: fn}
+}
+
+func (c *ConfigMaps) Kind() client.Object {
+	return &corev1.ConfigMap{}
+}
+
+func (c *ConfigMaps) Reconcile(ctx *core.Context) (ctrl.Result, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, fmt.Errorf("configmaps component requires a metadata provider")
+	}
+
+	desired, err := c.data(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for name, data := range desired {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ctx.Object.GetNamespace(),
+				Labels:    ctx.Metadata.StandardLabels(ctx.Object, c.component, nil),
+			},
+			Data: data,
+		}
+
+		if err = action.CreateOrUpdateOwnedResource(ctx, ctx.Object, cm); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot apply configmap %s: %w", name, err)
+		}
+	}
+
+	return ctrl.Result{}, c.prune(ctx, desired)
+}
+
+func (c *ConfigMaps) prune(ctx *core.Context, desired map[string]map[string]string) error {
+	cmList := &corev1.ConfigMapList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(ctx.Object.GetNamespace()),
+		client.MatchingLabels(ctx.Metadata.MatchLabels(ctx.Object, c.component)),
+	}
+	if err := ctx.Client.List(ctx, cmList, listOpts...); err != nil {
+		return err
+	}
+
+	var stale []client.Object
+	for idx := range cmList.Items {
+		cm := &cmList.Items[idx]
+		if _, ok := desired[cm.Name]; ok || !metav1.IsControlledBy(cm, ctx.Object) {
+			continue
+		}
+		stale = append(stale, cm)
+	}
+
+	return action.DeleteIfExists(ctx, stale...)
+}
@@ -0,0 +1,92 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+func TestConfigMaps(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+
+	desired := map[string]map[string]string{
+		"settings": {"level": "info"},
+		"scripts":  {"run.sh": "echo"},
+	}
+	comp := NewConfigMaps("config", func(*core.Context) (map[string]map[string]string, error) {
+		return desired, nil
+	})
+
+	get := func(name string) (*corev1.ConfigMap, error) {
+		cm := &corev1.ConfigMap{}
+		return cm, ctx.Client.Get(ctx, client.ObjectKey{Namespace: "team", Name: name}, cm)
+	}
+
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for name, data := range desired {
+		cm, err := get(name)
+		if err != nil {
+			t.Fatalf("configmap %s not created: %v", name, err)
+		}
+		if !reflect.DeepEqual(cm.Data, data) {
+			t.Errorf("configmap %s data = %v, want %v", name, cm.Data, data)
+		}
+		if !metav1.IsControlledBy(cm, owner) {
+			t.Errorf("configmap %s is not controlled by the owner", name)
+		}
+		for k, v := range ctx.Metadata.MatchLabels(owner, "config") {
+			if cm.Labels[k] != v {
+				t.Errorf("configmap %s labels = %v, want %s=%s", name, cm.Labels, k, v)
+			}
+		}
+	}
+
+	desired = map[string]map[string]string{"settings": {"level": "debug"}}
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	cm, err := get("settings")
+	if err != nil {
+		t.Fatalf("cannot get configmap: %v", err)
+	}
+	if cm.Data["level"] != "debug" {
+		t.Errorf("configmap data = %v, want the updated data", cm.Data)
+	}
+	if _, err = get("scripts"); !apierrors.IsNotFound(err) {
+		t.Errorf("stale configmap not pruned: %v", err)
+	}
+}
+
+func TestConfigMapsPruneKeepsUncontrolled(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+
+	// matches the component's labels but is not controlled by the owner
+	foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "foreign",
+		Namespace: "team",
+		Labels:    ctx.Metadata.MatchLabels(owner, "config"),
+	}}
+	if err := ctx.Client.Create(ctx, foreign); err != nil {
+		t.Fatalf("cannot create configmap: %v", err)
+	}
+
+	comp := NewConfigMaps("config", func(*core.Context) (map[string]map[string]string, error) {
+		return nil, nil
+	})
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(foreign), &corev1.ConfigMap{}); err != nil {
+		t.Errorf("configmap not controlled by the owner was pruned: %v", err)
+	}
+}
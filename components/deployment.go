@@ -0,0 +1,120 @@
+package components
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/action"
+	"github.com/dominodatalab/controller-util/collection"
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+const (
+	// DeploymentRolledOutConditionType reports whether the managed Deployment has rolled out all desired replicas.
+	DeploymentRolledOutConditionType = "DeploymentRolledOut"
+	// DeploymentRolloutCompleteReason indicates the rollout finished.
+	DeploymentRolloutCompleteReason = "RolloutComplete"
+	// DeploymentRolloutProgressingReason indicates the rollout is still in progress.
+	DeploymentRolloutProgressingReason = "RolloutProgressing"
+)
+
+// DeploymentFunc returns the desired Deployment.
+type DeploymentFunc func(*core.Context) (*appsv1.Deployment, error)
+
+// Deployment manages a Deployment owned by the reconcile object. The Deployment defaults to the provider's instance
+// name for the component and the standard labels are merged into its metadata and pod template. Since the selector of
+// a Deployment is immutable, the selector of an existing Deployment is kept and only the desired selector of a new one
+// is applied. Rollout progress is reported using the DeploymentRolledOut condition.
+type Deployment struct {
+	component metadata.AppComponent
+	desired   DeploymentFunc
+}
+
+func NewDeployment(ac metadata.AppComponent, fn DeploymentFunc) *Deployment {
+	return &Deployment{component: ac, desired: fn}
+}
+
+func (c *Deployment) Kind() client.Object {
+	return &appsv1.Deployment{}
+}
+
+func (c *Deployment) Reconcile(ctx *core.Context) (ctrl.Result, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, fmt.Errorf("deployment component requires a metadata provider")
+	}
+
+	deploy, err := c.desired(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if deploy.Name == "" {
+		deploy.Name = ctx.Metadata.InstanceName(ctx.Object, c.component)
+	}
+	deploy.Namespace = ctx.Object.GetNamespace()
+	deploy.Labels = collection.MergeStringMaps(ctx.Metadata.StandardLabels(ctx.Object, c.component, nil), orEmpty(deploy.Labels))
+	deploy.Spec.Template.Labels = collection.MergeStringMaps(ctx.Metadata.StandardLabels(ctx.Object, c.component, nil),
+		orEmpty(deploy.Spec.Template.Labels))
+	if deploy.Spec.Selector == nil {
+		deploy.Spec.Selector = &metav1.LabelSelector{MatchLabels: ctx.Metadata.MatchLabels(ctx.Object, c.component)}
+	}
+
+	if err = c.preserveSelector(ctx, deploy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = action.CreateOrUpdateOwnedResource(ctx, ctx.Object, deploy); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot apply deployment %s: %w", deploy.Name, err)
+	}
+
+	// the cache may not have observed a newly created deployment yet
+	ready, err := ctx.DeploymentReady(deploy.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if ready {
+		ctx.Conditions.SetTrue(DeploymentRolledOutConditionType, DeploymentRolloutCompleteReason,
+			fmt.Sprintf("Deployment %s has rolled out", deploy.Name))
+	} else {
+		ctx.Conditions.SetFalse(DeploymentRolledOutConditionType, DeploymentRolloutProgressingReason,
+			fmt.Sprintf("Deployment %s is rolling out", deploy.Name))
+	}
+
+	return core.ReadinessResult(ready), nil
+}
+
+// preserveSelector keeps the selector of an existing Deployment and makes sure the pod template still matches it.
+func (c *Deployment) preserveSelector(ctx *core.Context, deploy *appsv1.Deployment) error {
+	current := &appsv1.Deployment{}
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(deploy), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !equality.Semantic.DeepEqual(current.Spec.Selector, deploy.Spec.Selector) {
+		ctx.Log.Info("Keeping immutable selector of existing deployment", "deployment", deploy.Name,
+			"selector", current.Spec.Selector, "desired", deploy.Spec.Selector)
+		deploy.Spec.Selector = current.Spec.Selector
+	}
+	if current.Spec.Selector != nil {
+		deploy.Spec.Template.Labels = collection.MergeStringMaps(current.Spec.Selector.MatchLabels, deploy.Spec.Template.Labels)
+	}
+
+	return nil
+}
+
+func orEmpty(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
@@ -0,0 +1,123 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+func TestDeployment(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+	var conditions []metav1.Condition
+	ctx.Conditions = core.NewConditionHelperWithAccessor(owner, func(client.Object) *[]metav1.Condition {
+		return &conditions
+	})
+	key := client.ObjectKey{Namespace: "team", Name: "owner-app-server"}
+
+	image := "server:1"
+	comp := NewDeployment("server", func(*core.Context) (*appsv1.Deployment, error) {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "server", Image: image}}},
+				},
+			},
+		}, nil
+	})
+
+	reconcile := func(step string, wantReady bool) *appsv1.Deployment {
+		t.Helper()
+
+		res, err := comp.Reconcile(ctx)
+		if err != nil {
+			t.Fatalf("%s: Reconcile() error = %v", step, err)
+		}
+		if err = ctx.Conditions.Flush(); err != nil {
+			t.Fatalf("%s: Flush() error = %v", step, err)
+		}
+		if res != core.ReadinessResult(wantReady) {
+			t.Errorf("%s: Reconcile() = %+v, want the readiness result for ready=%t", step, res, wantReady)
+		}
+		wantStatus, wantReason := metav1.ConditionFalse, DeploymentRolloutProgressingReason
+		if wantReady {
+			wantStatus, wantReason = metav1.ConditionTrue, DeploymentRolloutCompleteReason
+		}
+		cond := meta.FindStatusCondition(conditions, DeploymentRolledOutConditionType)
+		if cond == nil || cond.Status != wantStatus || cond.Reason != wantReason {
+			t.Errorf("%s: %s condition = %+v, want %s/%s", step, DeploymentRolledOutConditionType, cond, wantStatus, wantReason)
+		}
+
+		deploy := &appsv1.Deployment{}
+		if err = ctx.Client.Get(ctx, key, deploy); err != nil {
+			t.Fatalf("%s: cannot get deployment: %v", step, err)
+		}
+		return deploy
+	}
+
+	deploy := reconcile("create", false)
+	if !metav1.IsControlledBy(deploy, owner) {
+		t.Error("deployment is not controlled by the owner")
+	}
+	matchLabels := ctx.Metadata.MatchLabels(owner, "server")
+	if deploy.Spec.Selector == nil || !reflect.DeepEqual(deploy.Spec.Selector.MatchLabels, matchLabels) {
+		t.Errorf("selector = %v, want %v", deploy.Spec.Selector, matchLabels)
+	}
+	for k, v := range ctx.Metadata.StandardLabels(owner, "server", nil) {
+		if deploy.Labels[k] != v || deploy.Spec.Template.Labels[k] != v {
+			t.Errorf("labels = %v, template labels = %v, want standard label %s=%s", deploy.Labels,
+				deploy.Spec.Template.Labels, k, v)
+		}
+	}
+
+	// an autoscaler scales the deployment and the server defaults fields left unset, while the selector predates the
+	// current standard labels
+	replicas, historyLimit, deadline := int32(5), int32(10), int32(600)
+	legacySelector := &metav1.LabelSelector{MatchLabels: map[string]string{"legacy": "selector"}}
+	deploy.Spec.Replicas = &replicas
+	deploy.Spec.RevisionHistoryLimit = &historyLimit
+	deploy.Spec.ProgressDeadlineSeconds = &deadline
+	deploy.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	deploy.Spec.Selector = legacySelector
+	if err := ctx.Client.Update(ctx, deploy); err != nil {
+		t.Fatalf("cannot update deployment: %v", err)
+	}
+
+	image = "server:2"
+	deploy = reconcile("template update", false)
+	if got := deploy.Spec.Template.Spec.Containers[0].Image; got != "server:2" {
+		t.Errorf("image = %s, want the updated template", got)
+	}
+	if !reflect.DeepEqual(deploy.Spec.Selector, legacySelector) {
+		t.Errorf("selector = %v, want the immutable selector %v kept", deploy.Spec.Selector, legacySelector)
+	}
+	if deploy.Spec.Template.Labels["legacy"] != "selector" {
+		t.Errorf("template labels = %v, want them to match the kept selector", deploy.Spec.Template.Labels)
+	}
+	if deploy.Spec.Replicas == nil || *deploy.Spec.Replicas != replicas {
+		t.Errorf("replicas = %v, want the scaled replicas kept", deploy.Spec.Replicas)
+	}
+	if deploy.Spec.RevisionHistoryLimit == nil || *deploy.Spec.RevisionHistoryLimit != historyLimit ||
+		deploy.Spec.ProgressDeadlineSeconds == nil || *deploy.Spec.ProgressDeadlineSeconds != deadline ||
+		deploy.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		t.Errorf("spec = %+v, want server defaulted fields kept", deploy.Spec)
+	}
+
+	deploy.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: deploy.Generation,
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		AvailableReplicas:  replicas,
+	}
+	if err := ctx.Client.Status().Update(ctx, deploy); err != nil {
+		t.Fatalf("cannot update deployment status: %v", err)
+	}
+	reconcile("rolled out", true)
+}
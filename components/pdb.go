@@ -0,0 +1,90 @@
+package components
+
+import (
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/action"
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+// PodDisruptionBudgetSpecFunc returns the desired PodDisruptionBudget spec.
+type PodDisruptionBudgetSpecFunc func(*core.Context) (policyv1.PodDisruptionBudgetSpec, error)
+
+// PodDisruptionBudget manages a PodDisruptionBudget named after the provider's instance name for the component and
+// owned by the reconcile object. The policy/v1beta1 api is used on clusters that do not serve policy/v1.
+type PodDisruptionBudget struct {
+	component metadata.AppComponent
+	spec      PodDisruptionBudgetSpecFunc
+	v1beta1   bool
+}
+
+func NewPodDisruptionBudget(ac metadata.AppComponent, fn PodDisruptionBudgetSpecFunc) *PodDisruptionBudget {
+	return &PodDisruptionBudget{component: ac, spec: fn}
+}
+
+func (c *PodDisruptionBudget) Initialize(ctx *core.Context, bldr *ctrl.Builder) error {
+	gk := schema.GroupKind{Group: policyv1.GroupName, Kind: "PodDisruptionBudget"}
+	if _, err := ctx.RESTMapper.RESTMapping(gk, policyv1.SchemeGroupVersion.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return fmt.Errorf("cannot discover poddisruptionbudget api: %w", err)
+		}
+		ctx.Log.Info("Cluster does not serve policy/v1, using policy/v1beta1 PodDisruptionBudgets")
+		c.v1beta1 = true
+	}
+
+	bldr.Owns(c.kind())
+	return nil
+}
+
+func (c *PodDisruptionBudget) Reconcile(ctx *core.Context) (ctrl.Result, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, fmt.Errorf("poddisruptionbudget component requires a metadata provider")
+	}
+
+	spec, err := c.spec(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	name := ctx.Metadata.InstanceName(ctx.Object, c.component)
+	objMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: ctx.Object.GetNamespace(),
+		Labels:    ctx.Metadata.StandardLabels(ctx.Object, c.component, nil),
+	}
+
+	var pdb client.Object = &policyv1.PodDisruptionBudget{ObjectMeta: objMeta, Spec: spec}
+	if c.v1beta1 {
+		pdb = &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: objMeta,
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				MinAvailable:               spec.MinAvailable,
+				MaxUnavailable:             spec.MaxUnavailable,
+				Selector:                   spec.Selector,
+				UnhealthyPodEvictionPolicy: (*policyv1beta1.UnhealthyPodEvictionPolicyType)(spec.UnhealthyPodEvictionPolicy),
+			},
+		}
+	}
+
+	if err = action.CreateOrUpdateOwnedResource(ctx, ctx.Object, pdb); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot apply poddisruptionbudget %s: %w", name, err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (c *PodDisruptionBudget) kind() client.Object {
+	if c.v1beta1 {
+		return &policyv1beta1.PodDisruptionBudget{}
+	}
+	return &policyv1.PodDisruptionBudget{}
+}
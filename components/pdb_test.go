@@ -0,0 +1,132 @@
+package components
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+// failingMapper fails every lookup with an error other than a missing match.
+type failingMapper struct {
+	meta.RESTMapper
+}
+
+func (failingMapper) RESTMapping(schema.GroupKind, ...string) (*meta.RESTMapping, error) {
+	return nil, errors.New("discovery unavailable")
+}
+
+func TestPodDisruptionBudgetInitialize(t *testing.T) {
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "http://127.0.0.1:1"}, ctrl.Options{
+		Scheme:  clientgoscheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		t.Fatalf("cannot create manager: %v", err)
+	}
+
+	mapper := func(versions ...schema.GroupVersion) meta.RESTMapper {
+		m := meta.NewDefaultRESTMapper(versions)
+		for _, gv := range versions {
+			m.Add(gv.WithKind("PodDisruptionBudget"), meta.RESTScopeNamespace)
+		}
+		return m
+	}
+
+	cases := []struct {
+		name    string
+		mapper  meta.RESTMapper
+		v1beta1 bool
+		wantErr bool
+	}{
+		{name: "policy/v1", mapper: mapper(policyv1.SchemeGroupVersion, policyv1beta1.SchemeGroupVersion)},
+		{name: "policy/v1beta1 only", mapper: mapper(policyv1beta1.SchemeGroupVersion), v1beta1: true},
+		{name: "discovery error", mapper: failingMapper{}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team"}}
+			ctx := newTestContext(owner)
+			ctx.RESTMapper = tc.mapper
+
+			comp := NewPodDisruptionBudget("server", nil)
+			err := comp.Initialize(ctx, ctrl.NewControllerManagedBy(mgr))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Initialize() error = %v, want error %t", err, tc.wantErr)
+			}
+			if comp.v1beta1 != tc.v1beta1 {
+				t.Errorf("v1beta1 = %t, want %t", comp.v1beta1, tc.v1beta1)
+			}
+		})
+	}
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromInt(1)
+	policy := policyv1.AlwaysAllow
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MinAvailable:               &minAvailable,
+		Selector:                   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "server"}},
+		UnhealthyPodEvictionPolicy: &policy,
+	}
+
+	for _, v1beta1 := range []bool{false, true} {
+		name := "policy/v1"
+		if v1beta1 {
+			name = "policy/v1beta1"
+		}
+		t.Run(name, func(t *testing.T) {
+			owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+			ctx := newTestContext(owner)
+
+			comp := NewPodDisruptionBudget("server", func(*core.Context) (policyv1.PodDisruptionBudgetSpec, error) {
+				return spec, nil
+			})
+			comp.v1beta1 = v1beta1
+			if _, err := comp.Reconcile(ctx); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			key := client.ObjectKey{Namespace: "team", Name: "owner-app-server"}
+			var found client.Object
+			if v1beta1 {
+				pdb := &policyv1beta1.PodDisruptionBudget{}
+				if err := ctx.Client.Get(ctx, key, pdb); err != nil {
+					t.Fatalf("poddisruptionbudget not created: %v", err)
+				}
+				if pdb.Spec.MinAvailable.IntValue() != 1 || pdb.Spec.Selector.MatchLabels["app"] != "server" ||
+					pdb.Spec.UnhealthyPodEvictionPolicy == nil ||
+					*pdb.Spec.UnhealthyPodEvictionPolicy != policyv1beta1.AlwaysAllow {
+					t.Errorf("spec = %+v, want the converted spec", pdb.Spec)
+				}
+				found = pdb
+			} else {
+				pdb := &policyv1.PodDisruptionBudget{}
+				if err := ctx.Client.Get(ctx, key, pdb); err != nil {
+					t.Fatalf("poddisruptionbudget not created: %v", err)
+				}
+				if pdb.Spec.MinAvailable.IntValue() != 1 || pdb.Spec.Selector.MatchLabels["app"] != "server" {
+					t.Errorf("spec = %+v, want the desired spec", pdb.Spec)
+				}
+				found = pdb
+			}
+			if !metav1.IsControlledBy(found, owner) {
+				t.Error("poddisruptionbudget is not controlled by the owner")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package components
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/action"
+	"github.com/dominodatalab/controller-util/core"
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+// PolicyRulesFunc returns the rules granted to the managed ServiceAccount.
+type PolicyRulesFunc func(*core.Context) ([]rbacv1.PolicyRule, error)
+
+// RBAC manages a ServiceAccount along with a Role and RoleBinding granting it the supplied rules. All objects share the
+// provider's instance name for the component and are owned by the reconcile object. RoleBindings carrying the
+// component's match labels that do not bind the managed Role are pruned.
+type RBAC struct {
+	component metadata.AppComponent
+	rules     PolicyRulesFunc
+}
+
+func NewRBAC(ac metadata.AppComponent, fn PolicyRulesFunc) *RBAC {
+	return &RBAC{component: ac, rules: fn}
+}
+
+func (c *RBAC) Initialize(_ *core.Context, bldr *ctrl.Builder) error {
+	bldr.Owns(&corev1.ServiceAccount{}).Owns(&rbacv1.Role{}).Owns(&rbacv1.RoleBinding{})
+	return nil
+}
+
+func (c *RBAC) Reconcile(ctx *core.Context) (ctrl.Result, error) {
+	if ctx.Metadata == nil {
+		return ctrl.Result{}, fmt.Errorf("rbac component requires a metadata provider")
+	}
+
+	rules, err := c.rules(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	name := ctx.Metadata.InstanceName(ctx.Object, c.component)
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: ctx.Object.GetNamespace(),
+		Labels:    ctx.Metadata.StandardLabels(ctx.Object, c.component, nil),
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: *meta.DeepCopy()}
+	role := &rbacv1.Role{ObjectMeta: *meta.DeepCopy(), Rules: rules}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: *meta.DeepCopy(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: ctx.Object.GetNamespace(),
+			},
+		},
+	}
+
+	for _, obj := range []client.Object{sa, role, binding} {
+		if err = action.CreateOrUpdateOwnedResource(ctx, ctx.Object, obj); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot apply %T %s: %w", obj, name, err)
+		}
+	}
+
+	return ctrl.Result{}, c.pruneBindings(ctx, name)
+}
+
+func (c *RBAC) pruneBindings(ctx *core.Context, keep string) error {
+	bindingList := &rbacv1.RoleBindingList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(ctx.Object.GetNamespace()),
+		client.MatchingLabels(ctx.Metadata.MatchLabels(ctx.Object, c.component)),
+	}
+	if err := ctx.Client.List(ctx, bindingList, listOpts...); err != nil {
+		return err
+	}
+
+	var stale []client.Object
+	for idx := range bindingList.Items {
+		binding := &bindingList.Items[idx]
+		if binding.Name == keep || !metav1.IsControlledBy(binding, ctx.Object) {
+			continue
+		}
+		stale = append(stale, binding)
+	}
+
+	return action.DeleteIfExists(ctx, stale...)
+}
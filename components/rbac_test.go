@@ -0,0 +1,112 @@
+package components
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+func TestRBAC(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+	comp := NewRBAC("workload", func(*core.Context) ([]rbacv1.PolicyRule, error) {
+		return rules, nil
+	})
+	name := ctx.Metadata.InstanceName(owner, "workload")
+	key := client.ObjectKey{Namespace: "team", Name: name}
+
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	sa := &corev1.ServiceAccount{}
+	role := &rbacv1.Role{}
+	binding := &rbacv1.RoleBinding{}
+	for _, obj := range []client.Object{sa, role, binding} {
+		if err := ctx.Client.Get(ctx, key, obj); err != nil {
+			t.Fatalf("%T %s not created: %v", obj, name, err)
+		}
+		if !metav1.IsControlledBy(obj, owner) {
+			t.Errorf("%T %s is not controlled by the owner", obj, name)
+		}
+		for k, v := range ctx.Metadata.MatchLabels(owner, "workload") {
+			if obj.GetLabels()[k] != v {
+				t.Errorf("%T %s labels = %v, want %s=%s", obj, name, obj.GetLabels(), k, v)
+			}
+		}
+	}
+	if len(role.Rules) != 1 {
+		t.Errorf("role rules = %v, want the supplied rules", role.Rules)
+	}
+	if binding.RoleRef.Name != name || len(binding.Subjects) != 1 || binding.Subjects[0].Name != sa.Name {
+		t.Errorf("role binding = %+v, want the role bound to the service account", binding)
+	}
+
+	// reconciling the same rules again must not write anything
+	resourceVersions := []string{sa.ResourceVersion, role.ResourceVersion, binding.ResourceVersion}
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for i, obj := range []client.Object{&corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		if err := ctx.Client.Get(ctx, key, obj); err != nil {
+			t.Fatalf("cannot get %T: %v", obj, err)
+		}
+		if obj.GetResourceVersion() != resourceVersions[i] {
+			t.Errorf("%T updated by a no-op reconcile", obj)
+		}
+	}
+
+	rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}})
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := ctx.Client.Get(ctx, key, role); err != nil {
+		t.Fatalf("cannot get role: %v", err)
+	}
+	if len(role.Rules) != 2 {
+		t.Errorf("role rules = %v, want the updated rules", role.Rules)
+	}
+}
+
+func TestRBACPrunesBindings(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "team", UID: "owner-uid"}}
+	ctx := newTestContext(owner)
+
+	isController := true
+	labels := ctx.Metadata.MatchLabels(owner, "workload")
+	stale := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{
+		Name:      "stale",
+		Namespace: "team",
+		Labels:    labels,
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid", Controller: &isController,
+		}},
+	}}
+	foreign := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "team", Labels: labels}}
+	for _, obj := range []client.Object{stale, foreign} {
+		if err := ctx.Client.Create(ctx, obj); err != nil {
+			t.Fatalf("cannot create role binding: %v", err)
+		}
+	}
+
+	comp := NewRBAC("workload", func(*core.Context) ([]rbacv1.PolicyRule, error) {
+		return nil, nil
+	})
+	if _, err := comp.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(stale), &rbacv1.RoleBinding{}); !apierrors.IsNotFound(err) {
+		t.Errorf("stale role binding not pruned: %v", err)
+	}
+	if err := ctx.Client.Get(ctx, client.ObjectKeyFromObject(foreign), &rbacv1.RoleBinding{}); err != nil {
+		t.Errorf("role binding not controlled by the owner was pruned: %v", err)
+	}
+}
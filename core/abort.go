@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpecChangePollInterval is how often the reconcile object is re-fetched when WithAbortOnSpecChange is enabled.
+var SpecChangePollInterval = 5 * time.Second
+
+// watchSpecChange returns a context that is cancelled once the reconcile object's generation moves past generation,
+// along with a func reporting whether that happened.
+func (r *Reconciler) watchSpecChange(parent context.Context, req ctrl.Request, generation int64, log logr.Logger) (context.Context, func() bool, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	var changed int32
+	go func() {
+		ticker := time.NewTicker(SpecChangePollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			latest := r.apiType.DeepCopyObject().(client.Object)
+			if err := r.client.Get(ctx, req.NamespacedName, latest); err != nil {
+				continue
+			}
+			if latest.GetGeneration() > generation {
+				log.Info("Aborting reconcile, object spec changed", "generation", generation, "latestGeneration", latest.GetGeneration())
+				atomic.StoreInt32(&changed, 1)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return ctx, func() bool { return atomic.LoadInt32(&changed) == 1 }, cancel
+}
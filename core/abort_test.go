@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestAbortOnSpecChange(t *testing.T) {
+	defer func(interval time.Duration) { SpecChangePollInterval = interval }(SpecChangePollInterval)
+	SpecChangePollInterval = 10 * time.Millisecond
+
+	obj := newTestObject("aborted")
+	obj.Generation = 1
+
+	// once bumped, every get observes a newer generation, as if the spec was edited while components run
+	var bumped int32
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, o client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, o, opts...); err != nil {
+				return err
+			}
+			if atomic.LoadInt32(&bumped) == 1 {
+				o.SetGeneration(2)
+			}
+			return nil
+		},
+	}, obj)
+
+	var cancelled, ranAfter bool
+	r := tr.build("aborted", func(r *Reconciler) {
+		r.WithAbortOnSpecChange()
+		r.Component("long", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			atomic.StoreInt32(&bumped, 1)
+			select {
+			case <-ctx.Done():
+				cancelled = true
+				return ctrl.Result{}, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return ctrl.Result{}, nil
+			}
+		}))
+		r.Component("after", componentFunc(func(*Context) (ctrl.Result, error) {
+			ranAfter = true
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	res, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v, want the cancellation to be swallowed", err)
+	}
+	if !cancelled {
+		t.Error("context passed to the running component was not cancelled")
+	}
+	if ranAfter {
+		t.Error("remaining component ran after the spec changed")
+	}
+	if !res.Requeue {
+		t.Errorf("Reconcile() = %+v, want a requeue", res)
+	}
+}
+
+func TestAbortOnSpecChangeUnchanged(t *testing.T) {
+	defer func(interval time.Duration) { SpecChangePollInterval = interval }(SpecChangePollInterval)
+	SpecChangePollInterval = 10 * time.Millisecond
+
+	obj := newTestObject("unchanged")
+	obj.Generation = 1
+	tr := newTestReconciler(t, obj)
+
+	var ranAfter bool
+	r := tr.build("unchanged", func(r *Reconciler) {
+		r.WithAbortOnSpecChange()
+		r.Component("long", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			// outlast several polls of the unchanged object
+			time.Sleep(5 * SpecChangePollInterval)
+			return ctrl.Result{}, ctx.Err()
+		}))
+		r.Component("after", componentFunc(func(*Context) (ctrl.Result, error) {
+			ranAfter = true
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	res, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+	if err != nil || res.Requeue {
+		t.Fatalf("Reconcile() = %+v, %v, want no requeue", res, err)
+	}
+	if !ranAfter {
+		t.Error("remaining component skipped although the spec did not change")
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -21,3 +24,85 @@ type InitializerComponent interface {
 type FinalizerComponent interface {
 	Finalize(*Context) (ctrl.Result, bool, error)
 }
+
+// FinalizerRetryInterval is the requeue interval used when a finalizer is not done and requested no requeue itself.
+// It defaults to zero, i.e. such a finalizer only runs again when the object changes.
+var FinalizerRetryInterval time.Duration
+
+// FinalizerRetryComponent overrides FinalizerRetryInterval for the finalizers of a component, e.g. to poll a slow
+// external deletion less often.
+type FinalizerRetryComponent interface {
+	FinalizerRetryInterval() time.Duration
+}
+
+// FinalizerProgressComponent is an alternative to FinalizerComponent whose incomplete finalization reports a progress
+// message. Messages of all components are combined into the Finalizing condition of the reconcile object.
+type FinalizerProgressComponent interface {
+	FinalizeWithProgress(*Context) (res ctrl.Result, done bool, message string, err error)
+}
+
+type progressFinalizer struct {
+	FinalizerProgressComponent
+}
+
+func (p progressFinalizer) Finalize(ctx *Context) (ctrl.Result, bool, error) {
+	res, done, _, err := p.FinalizeWithProgress(ctx)
+	return res, done, err
+}
+
+// ConditionReportingComponent returns the conditions it wants set instead of setting them on the context. When a
+// component implements it, ReconcileConditions is called in place of Reconcile and the returned conditions are applied
+// and flushed after it returns.
+type ConditionReportingComponent interface {
+	ReconcileConditions(*Context) ([]metav1.Condition, ctrl.Result, error)
+}
+
+// MigrationAnnotationPrefix prefixes the per-component annotation recording the last completed migration version.
+const MigrationAnnotationPrefix = "migrations.controller-util.dominodatalab.com/"
+
+// MigrationComponent runs once per object and version. Completion is recorded in an annotation on the object after a
+// reconcile that returns neither an error nor a requeue; changing the version runs the migration again.
+type MigrationComponent interface {
+	Component
+	MigrationVersion() string
+}
+
+// SkipUnchangedComponent marks a component that may be bypassed when the reconcile object's resourceVersion has not
+// changed since the last successful reconcile. See Reconciler.WithResourceVersionSkip.
+type SkipUnchangedComponent interface {
+	SkipUnchanged() bool
+}
+
+// StatusOnlyComponent marks a component that only computes status and may be bypassed when the reconcile object's
+// generation has not changed since the last successful reconcile. See Reconciler.WithObjectGenerationSkip.
+type StatusOnlyComponent interface {
+	StatusOnly() bool
+}
+
+// MultiFinalizerComponent registers one finalizer per name returned by Finalizers. Each finalizer is finalized
+// independently and removed once FinalizeNamed reports it as done.
+type MultiFinalizerComponent interface {
+	Finalizers() []string
+	FinalizeNamed(ctx *Context, name string) (ctrl.Result, bool, error)
+}
+
+// ComponentSummaryContextDataKey is the ContextData key holding the *ComponentSummary of the current reconcile.
+const ComponentSummaryContextDataKey = "controller-util.dominodatalab.com/component-summary"
+
+// ComponentSummary records which components ran during a reconcile and why others were skipped.
+type ComponentSummary struct {
+	Executed []string
+	Skipped  map[string]string
+}
+
+func newComponentSummary() *ComponentSummary {
+	return &ComponentSummary{Skipped: map[string]string{}}
+}
+
+func (s *ComponentSummary) executed(name string) {
+	s.Executed = append(s.Executed, name)
+}
+
+func (s *ComponentSummary) skipped(name, reason string) {
+	s.Skipped[name] = reason
+}
@@ -0,0 +1,507 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retryFinalizer is a finalizer that never completes and requests the given retry interval.
+type retryFinalizer struct {
+	finalizerFunc
+	interval time.Duration
+}
+
+func (f retryFinalizer) FinalizerRetryInterval() time.Duration {
+	return f.interval
+}
+
+func TestFinalizerRetry(t *testing.T) {
+	defer func(d time.Duration) { FinalizerRetryInterval = d }(FinalizerRetryInterval)
+
+	plain := &reconcilerComponent{name: "plain", comp: finalizerFunc{}}
+	cadence := &reconcilerComponent{name: "cadence", comp: retryFinalizer{interval: time.Minute}}
+
+	cases := []struct {
+		name   string
+		global time.Duration
+		rc     *reconcilerComponent
+		res    ctrl.Result
+		done   bool
+		want   ctrl.Result
+	}{
+		{name: "no retry by default", rc: plain},
+		{name: "global interval", global: 5 * time.Second, rc: plain, want: ctrl.Result{RequeueAfter: 5 * time.Second}},
+		{name: "component cadence", rc: cadence, want: ctrl.Result{RequeueAfter: time.Minute}},
+		{name: "component cadence wins", global: 5 * time.Second, rc: cadence, want: ctrl.Result{RequeueAfter: time.Minute}},
+		{
+			name: "component result wins", global: 5 * time.Second, rc: cadence,
+			res: ctrl.Result{RequeueAfter: time.Second}, want: ctrl.Result{RequeueAfter: time.Second},
+		},
+		{name: "done", global: 5 * time.Second, rc: cadence, done: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			FinalizerRetryInterval = tc.global
+			if got := finalizerRetry(tc.rc, tc.res, tc.done); got != tc.want {
+				t.Errorf("finalizerRetry() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReconcileRequeuesFinalizerWithComponentCadence(t *testing.T) {
+	defer func(d time.Duration) { FinalizerRetryInterval = d }(FinalizerRetryInterval)
+	FinalizerRetryInterval = 5 * time.Second
+
+	obj := newTestObject("cadence")
+	obj.Finalizers = []string{"cadence.test.dominodatalab.com/slow"}
+	obj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	tr := newTestReconciler(t, obj)
+
+	r := tr.build("cadence", func(r *Reconciler) {
+		r.Component("slow", retryFinalizer{
+			finalizerFunc: finalizerFunc{
+				componentFunc: func(*Context) (ctrl.Result, error) {
+					return ctrl.Result{}, nil
+				},
+				finalize: func(*Context) (ctrl.Result, bool, error) {
+					return ctrl.Result{}, false, nil
+				},
+			},
+			interval: time.Minute,
+		})
+	})
+
+	res, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if res.RequeueAfter != time.Minute {
+		t.Errorf("RequeueAfter = %v, want the component cadence %v", res.RequeueAfter, time.Minute)
+	}
+}
+
+func TestComponentSummary(t *testing.T) {
+	deleting := newTestObject("summary")
+	deleting.Finalizers = []string{"summary.test.dominodatalab.com/cleanup"}
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	tr := newTestReconciler(t, deleting)
+
+	var summary *ComponentSummary
+	noop := componentFunc(func(*Context) (ctrl.Result, error) {
+		return ctrl.Result{}, nil
+	})
+	r := tr.build("summary", func(r *Reconciler) {
+		r.Component("plain", noop)
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: noop,
+			finalize: func(ctx *Context) (ctrl.Result, bool, error) {
+				summary = ctx.Data[ComponentSummaryContextDataKey].(*ComponentSummary)
+				return ctrl.Result{}, true, nil
+			},
+		})
+		r.Component("other", finalizerFunc{componentFunc: noop})
+	})
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(deleting)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if summary == nil {
+		t.Fatal("component summary not available in context data")
+	}
+	if want := []string{"cleanup"}; !reflect.DeepEqual(summary.Executed, want) {
+		t.Errorf("executed = %v, want %v", summary.Executed, want)
+	}
+	want := map[string]string{"plain": "object is being deleted", "other": "finalizer already removed"}
+	if !reflect.DeepEqual(summary.Skipped, want) {
+		t.Errorf("skipped = %v, want %v", summary.Skipped, want)
+	}
+}
+
+func TestComponentSummaryIsLogged(t *testing.T) {
+	obj := newTestObject("summary-log")
+	tr := newTestReconciler(t, obj)
+
+	r := tr.build("summary-log", func(r *Reconciler) {
+		r.WithSuspendField(func(client.Object) bool { return true })
+		r.Component("suspended", componentFunc(func(*Context) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		}))
+	})
+	log, lines := captureLogs()
+	r.log = log
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	want := `"executed"=[] "skipped"={"suspended":"object is suspended"}`
+	for _, line := range *lines {
+		if strings.Contains(line, "Reconciliation complete") {
+			if !strings.Contains(line, want) {
+				t.Errorf("completion log %q does not contain %s", line, want)
+			}
+			return
+		}
+	}
+	t.Errorf("completion not logged, got %v", *lines)
+}
+
+// multiFinalizer manages one finalizer per key of done, each completing as configured.
+type multiFinalizer struct {
+	componentFunc
+	done      map[string]bool
+	finalized []string
+}
+
+func (f *multiFinalizer) Finalizers() []string {
+	return []string{"dns", "storage", "billing"}
+}
+
+func (f *multiFinalizer) FinalizeNamed(_ *Context, name string) (ctrl.Result, bool, error) {
+	f.finalized = append(f.finalized, name)
+	return ctrl.Result{}, f.done[name], nil
+}
+
+func TestMultiFinalizerComponent(t *testing.T) {
+	obj := newTestObject("multi")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	comp := &multiFinalizer{
+		componentFunc: func(*Context) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		},
+		done: map[string]bool{"dns": true, "storage": false, "billing": true},
+	}
+	r := tr.build("multi", func(r *Reconciler) {
+		r.Component("external", comp)
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := []string{
+		"multi.test.dominodatalab.com/external-dns",
+		"multi.test.dominodatalab.com/external-storage",
+		"multi.test.dominodatalab.com/external-billing",
+	}
+	stored := tr.get(key)
+	if !reflect.DeepEqual(stored.Finalizers, want) {
+		t.Fatalf("finalizers = %v, want %v", stored.Finalizers, want)
+	}
+
+	if err := tr.client.Delete(context.Background(), stored); err != nil {
+		t.Fatalf("cannot delete object: %v", err)
+	}
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if wantRan := []string{"dns", "storage", "billing"}; !reflect.DeepEqual(comp.finalized, wantRan) {
+		t.Errorf("finalized = %v, want %v", comp.finalized, wantRan)
+	}
+	want = []string{"multi.test.dominodatalab.com/external-storage"}
+	if got := tr.get(key).Finalizers; !reflect.DeepEqual(got, want) {
+		t.Errorf("finalizers = %v, want only the incomplete finalizer kept %v", got, want)
+	}
+
+	comp.finalized = nil
+	comp.done["storage"] = true
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if want := []string{"storage"}; !reflect.DeepEqual(comp.finalized, want) {
+		t.Errorf("finalized = %v, want only the pending finalizer %v", comp.finalized, want)
+	}
+}
+
+// skipUnchangedFunc is a component that may be bypassed while the resourceVersion is unchanged.
+type skipUnchangedFunc struct {
+	componentFunc
+}
+
+func (skipUnchangedFunc) SkipUnchanged() bool {
+	return true
+}
+
+func TestResourceVersionSkip(t *testing.T) {
+	obj := newTestObject("rv-skip")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var ran []string
+	requeue := false
+	r := tr.build("rv-skip", func(r *Reconciler) {
+		r.WithResourceVersionSkip()
+		r.Component("expensive", skipUnchangedFunc{func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "expensive")
+			return ctrl.Result{}, nil
+		}})
+		r.Component("cheap", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "cheap")
+			if requeue {
+				return ctrl.Result{RequeueAfter: time.Minute}, nil
+			}
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	steps := []struct {
+		name   string
+		before func()
+		want   []string
+	}{
+		{name: "first reconcile", want: []string{"expensive", "cheap"}},
+		{name: "unchanged", want: []string{"cheap"}},
+		{
+			name: "changed",
+			before: func() {
+				stored := tr.get(key)
+				stored.Spec.Replicas = 2
+				if err := tr.client.Update(context.Background(), stored); err != nil {
+					t.Fatalf("cannot update object: %v", err)
+				}
+			},
+			want: []string{"expensive", "cheap"},
+		},
+		{name: "unchanged after change", before: func() { requeue = true }, want: []string{"cheap"}},
+		// the previous reconcile requested a requeue, so no resourceVersion was recorded
+		{name: "time-based requeue", want: []string{"expensive", "cheap"}},
+	}
+
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		ran = nil
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("%s: Reconcile() error = %v", step.name, err)
+		}
+		if !reflect.DeepEqual(ran, step.want) {
+			t.Errorf("%s: ran %v, want %v", step.name, ran, step.want)
+		}
+	}
+}
+
+// statusOnlyFunc is a component that only computes status.
+type statusOnlyFunc struct {
+	componentFunc
+}
+
+func (statusOnlyFunc) StatusOnly() bool {
+	return true
+}
+
+func TestObjectGenerationSkip(t *testing.T) {
+	obj := newTestObject("generation-skip")
+	obj.Generation = 1
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var ran []string
+	r := tr.build("generation-skip", func(r *Reconciler) {
+		r.WithObjectGenerationSkip()
+		r.Component("status", statusOnlyFunc{func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "status")
+			return ctrl.Result{}, nil
+		}})
+		r.Component("workload", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "workload")
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	update := func(mutate func(*testObject)) func() {
+		return func() {
+			stored := tr.get(key)
+			mutate(stored)
+			if err := tr.client.Update(context.Background(), stored); err != nil {
+				t.Fatalf("cannot update object: %v", err)
+			}
+		}
+	}
+
+	steps := []struct {
+		name   string
+		before func()
+		want   []string
+	}{
+		{name: "first reconcile", want: []string{"status", "workload"}},
+		{name: "same generation", want: []string{"workload"}},
+		{
+			name: "metadata change",
+			before: update(func(obj *testObject) {
+				obj.Labels = map[string]string{"example.com/labeled": "true"}
+			}),
+			want: []string{"workload"},
+		},
+		{
+			name: "spec change",
+			before: update(func(obj *testObject) {
+				obj.Spec.Replicas = 2
+				obj.Generation++
+			}),
+			want: []string{"status", "workload"},
+		},
+	}
+
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		ran = nil
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("%s: Reconcile() error = %v", step.name, err)
+		}
+		if !reflect.DeepEqual(ran, step.want) {
+			t.Errorf("%s: ran %v, want %v", step.name, ran, step.want)
+		}
+	}
+}
+
+// migrationFunc is a migration component reporting the configured version.
+type migrationFunc struct {
+	componentFunc
+	version *string
+}
+
+func (m migrationFunc) MigrationVersion() string {
+	return *m.version
+}
+
+func TestMigrationComponent(t *testing.T) {
+	obj := newTestObject("migrated")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	annotation := MigrationAnnotationPrefix + "migrate"
+
+	version := "v1"
+	runs := 0
+	var failure error
+	r := tr.build("migrated", func(r *Reconciler) {
+		r.WithSuspendField(func(obj client.Object) bool {
+			return obj.(*testObject).Spec.Paused
+		})
+		r.Component("migrate", migrationFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) {
+				runs++
+				return ctrl.Result{}, failure
+			},
+			version: &version,
+		})
+	})
+	reconcile := func() {
+		t.Helper()
+		if _, err := tr.reconcile(r, key); err != nil && !errors.Is(err, failure) {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	failure = errors.New("backfill failed")
+	reconcile()
+	if got, ok := tr.get(key).Annotations[annotation]; ok {
+		t.Errorf("migration recorded as %q although it failed", got)
+	}
+
+	failure = nil
+	reconcile()
+	reconcile()
+	if runs != 2 {
+		t.Errorf("migration ran %d times, want once after the failure and never again", runs)
+	}
+	if got := tr.get(key).Annotations[annotation]; got != "v1" {
+		t.Errorf("migration annotation = %q, want v1", got)
+	}
+
+	stored := tr.get(key)
+	stored.Spec.Paused = true
+	if err := tr.client.Update(context.Background(), stored); err != nil {
+		t.Fatalf("cannot update object: %v", err)
+	}
+	version = "v2"
+	reconcile()
+	if runs != 2 || tr.get(key).Annotations[annotation] != "v1" {
+		t.Errorf("migration ran or was recorded while suspended: runs = %d, annotation = %q",
+			runs, tr.get(key).Annotations[annotation])
+	}
+
+	stored = tr.get(key)
+	stored.Spec.Paused = false
+	if err := tr.client.Update(context.Background(), stored); err != nil {
+		t.Fatalf("cannot update object: %v", err)
+	}
+	reconcile()
+	if runs != 3 || tr.get(key).Annotations[annotation] != "v2" {
+		t.Errorf("new migration version: runs = %d, annotation = %q, want 3 runs and v2",
+			runs, tr.get(key).Annotations[annotation])
+	}
+}
+
+// progressFinalizerFunc reports finalization progress until remaining reaches zero.
+type progressFinalizerFunc struct {
+	componentFunc
+	remaining *int
+}
+
+func (f progressFinalizerFunc) FinalizeWithProgress(*Context) (ctrl.Result, bool, string, error) {
+	if *f.remaining > 0 {
+		msg := fmt.Sprintf("waiting for %d pods", *f.remaining)
+		*f.remaining--
+		return ctrl.Result{RequeueAfter: time.Second}, false, msg, nil
+	}
+	return ctrl.Result{}, true, "", nil
+}
+
+func TestFinalizerProgressComponent(t *testing.T) {
+	obj := newTestObject("progress")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	remaining := 2
+	r := tr.build("progress", func(r *Reconciler) {
+		r.Component("drain", progressFinalizerFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) { return ctrl.Result{}, nil },
+			remaining:     &remaining,
+		})
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := tr.client.Delete(context.Background(), tr.get(key)); err != nil {
+		t.Fatalf("cannot delete object: %v", err)
+	}
+
+	for _, want := range []string{"drain: waiting for 2 pods", "drain: waiting for 1 pods"} {
+		res, err := tr.reconcile(r, key)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if res.RequeueAfter != time.Second {
+			t.Errorf("RequeueAfter = %v, want the finalizer's requeue", res.RequeueAfter)
+		}
+
+		cond := FindStatusCondition(tr.get(key).Status.Conditions, FinalizingConditionType)
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != FinalizingInProgressReason || cond.Message != want {
+			t.Errorf("Finalizing condition = %+v, want message %q", cond, want)
+		}
+	}
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := tr.client.Get(context.Background(), key, &testObject{}); !apierrors.IsNotFound(err) {
+		t.Errorf("object not removed once finalized: %v", err)
+	}
+}
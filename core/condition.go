@@ -2,8 +2,10 @@ package core
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -12,42 +14,107 @@ type ConditionObject interface {
 	GetConditions() *[]metav1.Condition
 }
 
+// ConditionsAccessor returns a pointer to an object's conditions, or nil when the object has none.
+type ConditionsAccessor func(client.Object) *[]metav1.Condition
+
+// ConditionObjectAccessor is the default ConditionsAccessor backed by the ConditionObject interface.
+func ConditionObjectAccessor(obj client.Object) *[]metav1.Condition {
+	if condObj, ok := obj.(ConditionObject); ok {
+		return condObj.GetConditions()
+	}
+	return nil
+}
+
+// ComponentConditionsAccessor returns a pointer to the conditions a component reports under its own status
+// sub-object, or nil to fall back to the object's top-level conditions.
+type ComponentConditionsAccessor func(obj client.Object, component string) *[]metav1.Condition
+
 type conditionHelper struct {
-	obj     client.Object
-	pending map[string]metav1.Condition
+	obj      client.Object
+	pending  map[string]metav1.Condition
+	clear    bool
+	accessor ConditionsAccessor
+	prefix   string
+
+	log          logr.Logger
+	allowedTypes map[string]struct{}
+	strictTypes  bool
+	rejected     []string
 }
 
 func NewConditionHelper(obj client.Object) *conditionHelper {
+	return NewConditionHelperWithAccessor(obj, ConditionObjectAccessor)
+}
+
+func NewConditionHelperWithAccessor(obj client.Object, accessor ConditionsAccessor) *conditionHelper {
 	return &conditionHelper{
-		obj:     obj,
-		pending: map[string]metav1.Condition{},
+		obj:      obj,
+		pending:  map[string]metav1.Condition{},
+		accessor: accessor,
 	}
 }
 
 func (h *conditionHelper) Flush() error {
+	var err error
+	if len(h.rejected) > 0 {
+		err = fmt.Errorf("condition types not allowed: %s", strings.Join(h.rejected, ", "))
+		h.rejected = nil
+	}
+
 	// NOTE: what do we do if obj does not adhere to interface, assuming they have not conditions?
-	condObj, ok := h.obj.(ConditionObject)
-	if !ok {
-		return nil
+	conditions := h.accessor(h.obj)
+	if conditions == nil {
+		return err
 	}
 
-	for _, cond := range h.pending {
-		SetStatusCondition(condObj.GetConditions(), cond)
+	if h.clear {
+		previous := *conditions
+		*conditions = nil
+
+		for _, cond := range h.pending {
+			if prev := FindStatusCondition(previous, cond.Type); prev != nil && prev.Status == cond.Status && cond.LastTransitionTime.IsZero() {
+				cond.LastTransitionTime = prev.LastTransitionTime
+			}
+			SetStatusCondition(conditions, cond)
+		}
+	} else {
+		for _, cond := range h.pending {
+			SetStatusCondition(conditions, cond)
+		}
 	}
 
 	h.pending = map[string]metav1.Condition{}
-	return nil
+	h.clear = false
+	return err
 }
 
-func (h *conditionHelper) SetCondition(cond metav1.Condition) {
+// Clear discards the object's existing conditions on the next Flush so that only conditions set afterwards remain.
+func (h *conditionHelper) Clear() {
+	h.clear = true
+}
+
+// SetCondition queues cond for the next Flush. A non-zero LastTransitionTime is honored as described for
+// SetStatusCondition, which makes it possible to replay or import conditions.
+func (h *conditionHelper) SetCondition(cond metav1.Condition) *conditionHelper {
+	if _, ok := h.allowedTypes[cond.Type]; h.allowedTypes != nil && !ok {
+		if h.strictTypes {
+			h.rejected = append(h.rejected, cond.Type)
+			return h
+		}
+		h.log.Info("Setting condition type that is not allowed", "type", cond.Type)
+	}
+	if h.prefix != "" && !strings.HasPrefix(cond.Type, h.prefix) {
+		cond.Type = h.prefix + cond.Type
+	}
 	if cond.ObservedGeneration == 0 {
 		cond.ObservedGeneration = h.obj.GetGeneration()
 	}
 	h.pending[cond.Type] = cond
+	return h
 }
 
-func (h *conditionHelper) Set(conditionType string, status metav1.ConditionStatus, reason, message string) {
-	h.SetCondition(metav1.Condition{
+func (h *conditionHelper) Set(conditionType string, status metav1.ConditionStatus, reason, message string) *conditionHelper {
+	return h.SetCondition(metav1.Condition{
 		Type:    conditionType,
 		Status:  status,
 		Reason:  reason,
@@ -55,32 +122,39 @@ func (h *conditionHelper) Set(conditionType string, status metav1.ConditionStatu
 	})
 }
 
-func (h *conditionHelper) Setf(conditionType string, status metav1.ConditionStatus, reason, message string, args ...interface{}) {
-	h.Set(conditionType, status, reason, fmt.Sprintf(message, args...))
+func (h *conditionHelper) Setf(conditionType string, status metav1.ConditionStatus, reason, message string, args ...interface{}) *conditionHelper {
+	return h.Set(conditionType, status, reason, fmt.Sprintf(message, args...))
 }
 
-func (h *conditionHelper) SetFalse(conditionType string, reason, message string) {
-	h.Set(conditionType, metav1.ConditionFalse, reason, message)
+func (h *conditionHelper) SetFalse(conditionType string, reason, message string) *conditionHelper {
+	return h.Set(conditionType, metav1.ConditionFalse, reason, message)
 }
 
-func (h *conditionHelper) SetTrue(conditionType string, reason, message string) {
-	h.Set(conditionType, metav1.ConditionTrue, reason, message)
+func (h *conditionHelper) SetTrue(conditionType string, reason, message string) *conditionHelper {
+	return h.Set(conditionType, metav1.ConditionTrue, reason, message)
 }
 
-func (h *conditionHelper) SetUnknown(conditionType string, reason, message string) {
-	h.Set(conditionType, metav1.ConditionUnknown, reason, message)
+func (h *conditionHelper) SetUnknown(conditionType string, reason, message string) *conditionHelper {
+	return h.Set(conditionType, metav1.ConditionUnknown, reason, message)
 }
 
-func (h *conditionHelper) SetfUnknown(conditionType string, reason, message string, args ...interface{}) {
-	h.Setf(conditionType, metav1.ConditionUnknown, reason, message, args...)
+func (h *conditionHelper) SetfUnknown(conditionType string, reason, message string, args ...interface{}) *conditionHelper {
+	return h.Setf(conditionType, metav1.ConditionUnknown, reason, message, args...)
 }
 
+// now returns the time used for condition transitions that do not carry a LastTransitionTime.
+var now = time.Now
+
+// SetStatusCondition adds newCondition to conditions or updates the existing condition of the same type. The
+// LastTransitionTime of a new condition is taken from newCondition when set and defaults to now. An existing condition
+// only transitions when its status changes, in which case it takes the provided time or now; otherwise its
+// LastTransitionTime is left untouched.
 func SetStatusCondition(conditions *[]metav1.Condition, newCondition metav1.Condition) {
 	existing := FindStatusCondition(*conditions, newCondition.Type)
 
 	if existing == nil {
 		if newCondition.LastTransitionTime.IsZero() {
-			newCondition.LastTransitionTime = metav1.NewTime(time.Now())
+			newCondition.LastTransitionTime = metav1.NewTime(now())
 		}
 
 		*conditions = append(*conditions, newCondition)
@@ -93,18 +167,33 @@ func SetStatusCondition(conditions *[]metav1.Condition, newCondition metav1.Cond
 		if !newCondition.LastTransitionTime.IsZero() {
 			existing.LastTransitionTime = newCondition.LastTransitionTime
 		} else {
-			existing.LastTransitionTime = metav1.NewTime(time.Now())
+			existing.LastTransitionTime = metav1.NewTime(now())
 		}
 	}
 
 	existing.Reason = newCondition.Reason
 	existing.Message = newCondition.Message
+	existing.ObservedGeneration = newCondition.ObservedGeneration
+}
+
+func RemoveStatusCondition(conditions *[]metav1.Condition, conditionType string) {
+	if FindStatusCondition(*conditions, conditionType) == nil {
+		return
+	}
+
+	filtered := make([]metav1.Condition, 0, len(*conditions)-1)
+	for _, cond := range *conditions {
+		if cond.Type != conditionType {
+			filtered = append(filtered, cond)
+		}
+	}
+	*conditions = filtered
 }
 
 func FindStatusCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
-	for _, cond := range conditions {
-		if cond.Type == conditionType {
-			return &cond
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
 		}
 	}
 
@@ -0,0 +1,511 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestFindStatusConditionReturnsElement(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Available", Status: metav1.ConditionTrue},
+		{Type: "Ready", Status: metav1.ConditionFalse},
+	}
+
+	cond := FindStatusCondition(conditions, "Ready")
+	if cond == nil {
+		t.Fatal("FindStatusCondition() = nil, want Ready condition")
+	}
+	cond.Status = metav1.ConditionTrue
+
+	if conditions[1].Status != metav1.ConditionTrue {
+		t.Errorf("modifying the returned condition did not update the slice")
+	}
+	if FindStatusCondition(conditions, "Missing") != nil {
+		t.Errorf("FindStatusCondition() returned a condition for a missing type")
+	}
+}
+
+func TestSetStatusConditionUpdatesExisting(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Pending", ObservedGeneration: 1},
+	}
+
+	SetStatusCondition(&conditions, metav1.Condition{
+		Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done", Message: "done", ObservedGeneration: 2,
+	})
+
+	if len(conditions) != 1 {
+		t.Fatalf("SetStatusCondition() added a condition, got %d", len(conditions))
+	}
+	cond := conditions[0]
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "Done" || cond.Message != "done" || cond.ObservedGeneration != 2 {
+		t.Errorf("SetStatusCondition() did not update the existing condition: %+v", cond)
+	}
+	if cond.LastTransitionTime.IsZero() {
+		t.Errorf("SetStatusCondition() did not set the transition time on a status change")
+	}
+}
+
+func TestReconcileUpdatesConditions(t *testing.T) {
+	obj := newTestObject("conditions")
+	tr := newTestReconciler(t, obj)
+
+	ready := false
+	r := tr.build("conditions", func(r *Reconciler) {
+		r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			if ready {
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+			} else {
+				ctx.Conditions.SetFalse("Ready", "Pending", "component is not ready")
+			}
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	key := client.ObjectKeyFromObject(obj)
+	for _, want := range []metav1.ConditionStatus{metav1.ConditionFalse, metav1.ConditionTrue} {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+
+		conditions := tr.get(key).Status.Conditions
+		cond := FindStatusCondition(conditions, "Ready")
+		if cond == nil {
+			t.Fatalf("Ready condition not found in %+v", conditions)
+		}
+		if cond.Status != want {
+			t.Errorf("Ready condition status = %s, want %s", cond.Status, want)
+		}
+		ready = true
+	}
+}
+
+func TestSetStatusConditionLastTransitionTime(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return clock }
+
+	supplied := metav1.NewTime(clock.Add(-time.Hour))
+	later := metav1.NewTime(clock.Add(-time.Minute))
+
+	var conditions []metav1.Condition
+	SetStatusCondition(&conditions, metav1.Condition{
+		Type: "Ready", Status: metav1.ConditionFalse, Reason: "Pending", LastTransitionTime: supplied,
+	})
+	if got := conditions[0].LastTransitionTime; !got.Equal(&supplied) {
+		t.Errorf("new condition LastTransitionTime = %v, want supplied %v", got, supplied)
+	}
+
+	SetStatusCondition(&conditions, metav1.Condition{
+		Type: "Ready", Status: metav1.ConditionFalse, Reason: "StillPending", LastTransitionTime: later,
+	})
+	if got := conditions[0].LastTransitionTime; !got.Equal(&supplied) {
+		t.Errorf("unchanged condition LastTransitionTime = %v, want %v", got, supplied)
+	}
+	SetStatusCondition(&conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "StillPending"})
+	if got := conditions[0].LastTransitionTime; !got.Equal(&supplied) {
+		t.Errorf("unchanged condition LastTransitionTime = %v, want %v", got, supplied)
+	}
+
+	SetStatusCondition(&conditions, metav1.Condition{
+		Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done", LastTransitionTime: later,
+	})
+	if got := conditions[0].LastTransitionTime; !got.Equal(&later) {
+		t.Errorf("transitioned condition LastTransitionTime = %v, want supplied %v", got, later)
+	}
+
+	SetStatusCondition(&conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Pending"})
+	if got := conditions[0].LastTransitionTime; !got.Time.Equal(clock) {
+		t.Errorf("transitioned condition LastTransitionTime = %v, want clock %v", got, clock)
+	}
+
+	SetStatusCondition(&conditions, metav1.Condition{Type: "Available", Status: metav1.ConditionTrue, Reason: "Done"})
+	if got := conditions[1].LastTransitionTime; !got.Time.Equal(clock) {
+		t.Errorf("new condition LastTransitionTime = %v, want clock %v", got, clock)
+	}
+}
+
+func TestConditionHelperClearPersists(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	obj := newTestObject("clear")
+	obj.Status.Conditions = []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done", LastTransitionTime: transition},
+		{Type: "Degraded", Status: metav1.ConditionFalse, Reason: "Healthy", LastTransitionTime: transition},
+	}
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	keepReady := true
+	r := tr.build("clear", func(r *Reconciler) {
+		r.Component("clear", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Conditions.Clear()
+			if keepReady {
+				ctx.Conditions.SetTrue("Ready", "StillDone", "component is ready")
+			}
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	conditions := tr.get(key).Status.Conditions
+	if len(conditions) != 1 || conditions[0].Type != "Ready" || conditions[0].Reason != "StillDone" {
+		t.Fatalf("conditions = %+v, want only the Ready condition set after Clear", conditions)
+	}
+	if !conditions[0].LastTransitionTime.Equal(&transition) {
+		t.Errorf("Ready LastTransitionTime = %v, want %v kept since its status did not change",
+			conditions[0].LastTransitionTime, transition)
+	}
+
+	keepReady = false
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if conditions := tr.get(key).Status.Conditions; len(conditions) != 0 {
+		t.Errorf("conditions = %+v, want all conditions removed", conditions)
+	}
+}
+
+// healthObject keeps its conditions under status.conditions and status.health and does not implement
+// ConditionObject.
+type healthObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status healthObjectStatus `json:"status,omitempty"`
+}
+
+type healthObjectStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Health     struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"health,omitempty"`
+}
+
+func (o *healthObject) DeepCopyObject() runtime.Object {
+	c := *o
+	o.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	c.Status.Conditions = append([]metav1.Condition(nil), o.Status.Conditions...)
+	c.Status.Health.Conditions = append([]metav1.Condition(nil), o.Status.Health.Conditions...)
+	return &c
+}
+
+type healthObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []healthObject `json:"items"`
+}
+
+func (l *healthObjectList) DeepCopyObject() runtime.Object {
+	c := *l
+	l.ListMeta.DeepCopyInto(&c.ListMeta)
+	c.Items = make([]healthObject, len(l.Items))
+	for i := range l.Items {
+		c.Items[i] = *l.Items[i].DeepCopyObject().(*healthObject)
+	}
+	return &c
+}
+
+func TestConditionsAccessor(t *testing.T) {
+	accessor := func(obj client.Object) *[]metav1.Condition {
+		return &obj.(*healthObject).Status.Health.Conditions
+	}
+
+	cases := []struct {
+		name     string
+		accessor ConditionsAccessor
+		want     int
+	}{
+		{name: "interface fallback", want: 0},
+		{name: "custom accessor", accessor: accessor, want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &healthObject{ObjectMeta: metav1.ObjectMeta{Name: "health", Namespace: "default"}}
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			r := NewReconciler(tr.mgr).For(&healthObject{}).Named("health")
+			r.client = tr.client
+			if tc.accessor != nil {
+				r.WithConditionsAccessor(tc.accessor)
+			}
+			r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+				return ctrl.Result{}, nil
+			}))
+			if _, err := r.Build(); err != nil {
+				t.Fatalf("cannot build reconciler: %v", err)
+			}
+
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			stored := &healthObject{}
+			if err := tr.client.Get(context.Background(), key, stored); err != nil {
+				t.Fatalf("cannot get object: %v", err)
+			}
+			conditions := stored.Status.Health.Conditions
+			if len(conditions) != tc.want {
+				t.Fatalf("conditions = %+v, want %d", conditions, tc.want)
+			}
+			if tc.want > 0 && (conditions[0].Type != "Ready" || conditions[0].Status != metav1.ConditionTrue) {
+				t.Errorf("condition = %+v, want Ready=True", conditions[0])
+			}
+		})
+	}
+}
+
+func TestConditionHelperChaining(t *testing.T) {
+	obj := newTestObject("chained")
+	h := NewConditionHelper(obj)
+
+	h.SetTrue("Ready", "Done", "ready").
+		SetFalse("Degraded", "Healthy", "not degraded").
+		SetUnknown("Progressing", "Pending", "waiting").
+		Setf("Available", metav1.ConditionTrue, "Serving", "%d replicas", 3)
+
+	if len(h.pending) != 4 {
+		t.Fatalf("pending = %+v, want all 4 chained conditions", h.pending)
+	}
+	if len(obj.Status.Conditions) != 0 {
+		t.Errorf("conditions = %+v, want nothing applied before Flush", obj.Status.Conditions)
+	}
+
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	assertCondition(t, obj, "Ready", metav1.ConditionTrue)
+	assertCondition(t, obj, "Degraded", metav1.ConditionFalse)
+	assertCondition(t, obj, "Progressing", metav1.ConditionUnknown)
+	assertCondition(t, obj, "Available", metav1.ConditionTrue)
+	if cond := FindStatusCondition(obj.Status.Conditions, "Available"); cond != nil && cond.Message != "3 replicas" {
+		t.Errorf("Available message = %q, want the formatted message", cond.Message)
+	}
+}
+
+func TestComponentConditionPrefix(t *testing.T) {
+	cases := []struct {
+		name   string
+		prefix bool
+		want   []string
+	}{
+		{name: "disabled", want: []string{"Ready"}},
+		{name: "enabled", prefix: true, want: []string{"networking.Ready", "storage.Ready"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("prefixed")
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			ready := func(ctx *Context) (ctrl.Result, error) {
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+				return ctrl.Result{}, nil
+			}
+			r := tr.build("prefixed", func(r *Reconciler) {
+				if tc.prefix {
+					r.WithComponentConditionPrefix()
+				}
+				r.Component("networking", componentFunc(ready))
+				r.Component("storage", componentFunc(ready))
+			})
+
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			var got []string
+			for _, cond := range tr.get(key).Status.Conditions {
+				got = append(got, cond.Type)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("condition types = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAllowedConditionTypes(t *testing.T) {
+	cases := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+		want    []string
+	}{
+		{name: "lenient", want: []string{"Raedy", "Ready"}},
+		{name: "strict", strict: true, wantErr: true, want: []string{"Ready"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("allowed")
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			r := tr.build("allowed", func(r *Reconciler) {
+				r.WithAllowedConditionTypes("Ready")
+				if tc.strict {
+					r.WithStrictConditionTypes()
+				}
+				r.Component("conditions", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+					ctx.Conditions.SetTrue("Raedy", "Done", "misspelled condition")
+					return ctrl.Result{}, nil
+				}))
+			})
+			log, lines := captureLogs()
+			r.log = log
+
+			_, err := tr.reconcile(r, key)
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "condition types not allowed: Raedy") {
+					t.Errorf("Reconcile() error = %v, want the rejected type reported", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			var got []string
+			for _, cond := range tr.get(key).Status.Conditions {
+				got = append(got, cond.Type)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("condition types = %v, want %v", got, tc.want)
+			}
+
+			warned := false
+			for _, line := range *lines {
+				warned = warned || strings.Contains(line, "Setting condition type that is not allowed")
+			}
+			if warned == tc.strict {
+				t.Errorf("logged disallowed type = %t, want %t", warned, !tc.strict)
+			}
+		})
+	}
+}
+
+// reportingComponent returns its conditions instead of setting them on the context.
+type reportingComponent struct {
+	reconciled bool
+	conditions []metav1.Condition
+	err        error
+}
+
+func (c *reportingComponent) Reconcile(*Context) (ctrl.Result, error) {
+	c.reconciled = true
+	return ctrl.Result{}, nil
+}
+
+func (c *reportingComponent) ReconcileConditions(*Context) ([]metav1.Condition, ctrl.Result, error) {
+	return c.conditions, ctrl.Result{RequeueAfter: time.Minute}, c.err
+}
+
+func TestConditionReportingComponent(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{name: "success"},
+		{name: "error", err: errors.New("partially degraded")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("reporting")
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			comp := &reportingComponent{
+				conditions: []metav1.Condition{
+					{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Degraded", Message: "one replica down"},
+					{Type: "Degraded", Status: metav1.ConditionTrue, Reason: "ReplicaDown", Message: "one replica down"},
+				},
+				err: tc.err,
+			}
+			r := tr.build("reporting", func(r *Reconciler) {
+				r.Component("report", comp)
+			})
+
+			res, err := tr.reconcile(r, key)
+			if !errors.Is(err, tc.err) || (tc.err == nil) != (err == nil) {
+				t.Fatalf("Reconcile() error = %v, want %v", err, tc.err)
+			}
+			if tc.err == nil && res.RequeueAfter != time.Minute {
+				t.Errorf("RequeueAfter = %v, want the returned result", res.RequeueAfter)
+			}
+			if comp.reconciled {
+				t.Error("Reconcile() called in place of ReconcileConditions()")
+			}
+
+			stored := tr.get(key)
+			assertCondition(t, stored, "Ready", metav1.ConditionFalse)
+			assertCondition(t, stored, "Degraded", metav1.ConditionTrue)
+		})
+	}
+}
+
+func TestComponentConditionsAccessor(t *testing.T) {
+	obj := &healthObject{ObjectMeta: metav1.ObjectMeta{Name: "health", Namespace: "default"}}
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := NewReconciler(tr.mgr).For(&healthObject{}).Named("health")
+	r.client = tr.client
+	r.WithConditionsAccessor(func(obj client.Object) *[]metav1.Condition {
+		return &obj.(*healthObject).Status.Conditions
+	})
+	r.WithComponentConditionsAccessor(func(obj client.Object, component string) *[]metav1.Condition {
+		if component == "probe" {
+			return &obj.(*healthObject).Status.Health.Conditions
+		}
+		return nil
+	})
+	r.Component("probe", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+		ctx.Conditions.SetTrue("Healthy", "ProbeSucceeded", "probe succeeded")
+		return ctrl.Result{}, nil
+	}))
+	r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+		ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+		return ctrl.Result{}, nil
+	}))
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	stored := &healthObject{}
+	if err := tr.client.Get(context.Background(), key, stored); err != nil {
+		t.Fatalf("cannot get object: %v", err)
+	}
+
+	types := func(conditions []metav1.Condition) []string {
+		var result []string
+		for _, c := range conditions {
+			result = append(result, c.Type)
+		}
+		return result
+	}
+	if got := types(stored.Status.Health.Conditions); !reflect.DeepEqual(got, []string{"Healthy"}) {
+		t.Errorf("component conditions = %v, want [Healthy]", got)
+	}
+	if got := types(stored.Status.Conditions); !reflect.DeepEqual(got, []string{"Ready"}) {
+		t.Errorf("top-level conditions = %v, want [Ready]", got)
+	}
+}
@@ -2,14 +2,29 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/dominodatalab/controller-util/collection"
+	"github.com/dominodatalab/controller-util/metadata"
 )
 
+// ContextData holds values shared by the components of a reconcile. It is copied for every reconcile, hence writes
+// are only visible to components running later in the same reconcile.
 type ContextData map[string]interface{}
 
 type Context struct {
@@ -19,9 +34,172 @@ type Context struct {
 	Data       ContextData
 	Patch      *Patch
 	Object     client.Object
+	Original   client.Object
 	Config     *rest.Config
 	Client     client.Client
 	Scheme     *runtime.Scheme
 	Recorder   record.EventRecorder
 	Conditions *conditionHelper
+	Metadata   *metadata.Provider
+	RESTMapper meta.RESTMapper
+
+	clientset    *clientsetCache
+	specRequeue  SpecRequeueIntervalFunc
+	fieldManager string
+	requeueOnce  time.Duration
+	reads        map[readKey]client.Object
+	elected      <-chan struct{}
+}
+
+type clientsetCache struct {
+	once sync.Once
+	cs   *kubernetes.Clientset
+	err  error
+}
+
+func (c *clientsetCache) get(config *rest.Config) (*kubernetes.Clientset, error) {
+	c.once.Do(func() {
+		c.cs, c.err = kubernetes.NewForConfig(config)
+	})
+	return c.cs, c.err
+}
+
+// Clientset returns a typed client-go clientset built from Config. The clientset is created once per reconciler and
+// shared across components.
+func (c *Context) Clientset() (*kubernetes.Clientset, error) {
+	if c.clientset == nil {
+		c.clientset = &clientsetCache{}
+	}
+	return c.clientset.get(c.Config)
+}
+
+// SpecRequeueInterval returns the requeue interval read from the object using the func configured with
+// Reconciler.WithSpecRequeueInterval. Non-positive intervals are ignored.
+func (c *Context) SpecRequeueInterval() (time.Duration, bool) {
+	if c.specRequeue == nil {
+		return 0, false
+	}
+
+	interval, ok := c.specRequeue(c.Object)
+	if !ok || interval <= 0 {
+		return 0, false
+	}
+	return interval, true
+}
+
+// IsLeader reports whether this manager instance has been elected leader. Managers without leader election are always
+// leader, as are contexts not created by a reconcile, e.g. the one passed to initializers.
+func (c *Context) IsLeader() bool {
+	if c.elected == nil {
+		return true
+	}
+
+	select {
+	case <-c.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequeueOnce schedules a single follow-up reconcile after d, regardless of the results returned by components. In
+// contrast to a periodic RequeueAfter result, calling it again during the follow-up reconcile has no effect unless the
+// object changed in between. The shortest duration wins when called multiple times.
+func (c *Context) RequeueOnce(d time.Duration) {
+	if d > 0 && (c.requeueOnce == 0 || d < c.requeueOnce) {
+		c.requeueOnce = d
+	}
+}
+
+// PatchChildStatus patches the status subresource of obj with its changes relative to original. No request is made
+// when the status is unchanged.
+func (c *Context) PatchChildStatus(obj, original client.Object) error {
+	patch := client.MergeFrom(original)
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	if string(data) == "{}" {
+		return nil
+	}
+
+	c.Forget(obj)
+	opts := &client.SubResourcePatchOptions{PatchOptions: client.PatchOptions{FieldManager: c.fieldManager}}
+	return c.Client.Status().Patch(c, obj, patch, opts)
+}
+
+// ResourceFor maps a kind to its resource using RESTMapper.
+func (c *Context) ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	mapping, err := c.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
+
+// Reference returns an ObjectReference to obj resolved using Scheme.
+func (c *Context) Reference(obj client.Object) (*corev1.ObjectReference, error) {
+	return reference.GetReference(c.Scheme, obj)
+}
+
+// AnnotatedEventf records an event for the reconcile object with the given annotations attached.
+func (c *Context) AnnotatedEventf(annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	c.Recorder.AnnotatedEventf(c.Object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// DriftCorrected records a Normal event on the reconcile object stating that out-of-band changes to a managed child
+// were reverted.
+func (c *Context) DriftCorrected(child client.Object) {
+	kind := fmt.Sprintf("%T", child)
+	if gvk, err := getGvk(child, c.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+	c.Recorder.Eventf(c.Object, corev1.EventTypeNormal, "DriftCorrected", "Reverted out-of-band changes to %s %s", kind,
+		client.ObjectKeyFromObject(child))
+}
+
+// Adopt takes ownership of an existing object by setting the controller reference to the reconcile object and merging
+// the standard labels from the configured metadata provider. Objects controlled by another owner are refused.
+func (c *Context) Adopt(obj client.Object, ac metadata.AppComponent) error {
+	return c.adopt(obj, ac, false)
+}
+
+// ForceAdopt behaves like Adopt but replaces any existing controller reference.
+func (c *Context) ForceAdopt(obj client.Object, ac metadata.AppComponent) error {
+	return c.adopt(obj, ac, true)
+}
+
+func (c *Context) adopt(obj client.Object, ac metadata.AppComponent, force bool) error {
+	base := obj.DeepCopyObject().(client.Object)
+
+	if owner := metav1.GetControllerOf(obj); owner != nil && owner.UID != c.Object.GetUID() {
+		if !force {
+			return &controllerutil.AlreadyOwnedError{Object: obj, Owner: *owner}
+		}
+
+		refs := obj.GetOwnerReferences()
+		for idx := range refs {
+			if refs[idx].UID == owner.UID {
+				refs = append(refs[:idx], refs[idx+1:]...)
+				break
+			}
+		}
+		obj.SetOwnerReferences(refs)
+	}
+
+	if err := controllerutil.SetControllerReference(c.Object, obj, c.Scheme); err != nil {
+		return err
+	}
+
+	if c.Metadata != nil {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		obj.SetLabels(collection.MergeStringMaps(c.Metadata.StandardLabels(c.Object, ac, nil), labels))
+	}
+
+	c.Log.V(1).Info("Adopting object", "object", client.ObjectKeyFromObject(obj))
+	c.Forget(obj)
+	return c.Client.Patch(c, obj, client.MergeFrom(base))
 }
@@ -0,0 +1,380 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+func TestRequeueOnceFiresOnce(t *testing.T) {
+	obj := newTestObject("requeue-once")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	runs := 0
+	r := tr.build("requeue-once", func(r *Reconciler) {
+		r.Component("recheck", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			// the status changes on every run, so the reconcile writes the object itself
+			runs++
+			ctx.Conditions.Setf("Checked", metav1.ConditionTrue, "Checked", "run %d", runs)
+			ctx.RequeueOnce(time.Minute)
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for i, want := range []time.Duration{time.Minute, 0, 0} {
+		res, err := tr.reconcile(r, key)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if res.RequeueAfter != want {
+			t.Errorf("reconcile %d: RequeueAfter = %v, want %v", i+1, res.RequeueAfter, want)
+		}
+	}
+
+	changed := tr.get(key)
+	changed.Labels = map[string]string{"example.com/changed": "true"}
+	if err := tr.client.Update(context.Background(), changed); err != nil {
+		t.Fatalf("cannot update object: %v", err)
+	}
+	if res, err := tr.reconcile(r, key); err != nil || res.RequeueAfter != time.Minute {
+		t.Errorf("Reconcile() after a change = %+v, %v, want RequeueAfter %v", res, err, time.Minute)
+	}
+}
+
+func TestContextIsLeader(t *testing.T) {
+	elected := make(chan struct{})
+	cases := []struct {
+		name    string
+		elected <-chan struct{}
+		want    bool
+	}{
+		{name: "no election channel", want: true},
+		{name: "not elected", elected: elected, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &Context{elected: tc.elected}
+			if got := ctx.IsLeader(); got != tc.want {
+				t.Errorf("IsLeader() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+
+	close(elected)
+	if ctx := (&Context{elected: elected}); !ctx.IsLeader() {
+		t.Errorf("IsLeader() = false after election")
+	}
+}
+
+func TestContextAdopt(t *testing.T) {
+	owner := newTestObject("owner")
+	owner.UID = "owner-uid"
+	other := newTestObject("other")
+	other.UID = "other-uid"
+
+	unowned := newTestObject("unowned")
+	owned := newTestObject("owned")
+	foreign := newTestObject("foreign")
+	scheme := newTestScheme(t)
+	if err := controllerutil.SetControllerReference(owner, owned, scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := controllerutil.SetControllerReference(other, foreign, scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	var patches []string
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			patches = append(patches, string(data))
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}, owner, unowned, owned, foreign)
+	ctx := &Context{Context: context.Background(), Log: logr.Discard(), Object: owner, Client: tr.client, Scheme: scheme}
+
+	t.Run("unowned", func(t *testing.T) {
+		patches = nil
+		obj := tr.get(client.ObjectKeyFromObject(unowned))
+		if err := ctx.Adopt(obj, metadata.AppComponentNone); err != nil {
+			t.Fatalf("Adopt() error = %v", err)
+		}
+		if stored := tr.get(client.ObjectKeyFromObject(unowned)); !metav1.IsControlledBy(stored, owner) {
+			t.Errorf("owner references = %v, want controlled by the owner", stored.OwnerReferences)
+		}
+		if len(patches) != 1 {
+			t.Errorf("patches = %v, want one", patches)
+		}
+	})
+
+	t.Run("already owned", func(t *testing.T) {
+		patches = nil
+		obj := tr.get(client.ObjectKeyFromObject(owned))
+		if err := ctx.Adopt(obj, metadata.AppComponentNone); err != nil {
+			t.Fatalf("Adopt() error = %v", err)
+		}
+		if len(obj.OwnerReferences) != 1 {
+			t.Errorf("owner references = %v, want only the existing one", obj.OwnerReferences)
+		}
+		if len(patches) != 1 || patches[0] != "{}" {
+			t.Errorf("patches = %v, want an empty patch", patches)
+		}
+	})
+
+	t.Run("owned by another controller", func(t *testing.T) {
+		patches = nil
+		obj := tr.get(client.ObjectKeyFromObject(foreign))
+		err := ctx.Adopt(obj, metadata.AppComponentNone)
+		var owned *controllerutil.AlreadyOwnedError
+		if !errors.As(err, &owned) {
+			t.Fatalf("Adopt() error = %v, want AlreadyOwnedError", err)
+		}
+		if len(patches) != 0 {
+			t.Errorf("patches = %v, want none", patches)
+		}
+		if stored := tr.get(client.ObjectKeyFromObject(foreign)); !metav1.IsControlledBy(stored, other) {
+			t.Errorf("owner references = %v, want still controlled by the other owner", stored.OwnerReferences)
+		}
+	})
+}
+
+func TestContextClientsetIsSharedAndLazy(t *testing.T) {
+	obj := newTestObject("clientset")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	call := false
+	var clientsets []*kubernetes.Clientset
+	r := tr.build("clientset", func(r *Reconciler) {
+		r.Component("clientset", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			if !call {
+				return ctrl.Result{}, nil
+			}
+			cs, err := ctx.Clientset()
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			clientsets = append(clientsets, cs)
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if r.clientset.cs != nil {
+		t.Errorf("clientset was created although no component requested it")
+	}
+
+	call = true
+	for i := 0; i < 2; i++ {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if len(clientsets) != 2 || clientsets[0] == nil || clientsets[0] != clientsets[1] {
+		t.Errorf("clientsets = %v, want the same clientset for every reconcile", clientsets)
+	}
+}
+
+func TestContextMetadataOverrideDoesNotLeak(t *testing.T) {
+	obj := newTestObject("metadata")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var creators []string
+	override := true
+	r := tr.build("metadata", func(r *Reconciler) {
+		r.WithMetadataProvider(metadata.NewProvider("app", metadata.WithCreator("controller")))
+		r.Component("override", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			if override {
+				ctx.Metadata = ctx.Metadata.Clone(metadata.WithCreator("override"))
+			}
+			return ctrl.Result{}, nil
+		}))
+		r.Component("record", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			creators = append(creators, ctx.Metadata.StandardLabels(ctx.Object, metadata.AppComponentNone, nil)[metadata.ApplicationCreatedByLabelKey])
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for _, o := range []bool{true, false} {
+		override = o
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	if want := []string{"override", "controller"}; !reflect.DeepEqual(creators, want) {
+		t.Errorf("creators seen by components = %v, want %v", creators, want)
+	}
+}
+
+func TestContextAnnotatedEventf(t *testing.T) {
+	obj := newTestObject("annotated")
+	tr := newTestReconciler(t, obj)
+	tr.events.IncludeObject = true
+
+	r := tr.build("annotated", func(r *Reconciler) {
+		r.Component("event", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.AnnotatedEventf(map[string]string{"example.com/revision": "3"}, corev1.EventTypeNormal, "RolledOut",
+				"Rolled out revision %d", 3)
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	want := "Normal RolledOut Rolled out revision 3 involvedObject{kind=testObject,apiVersion=test.dominodatalab.com/v1} " +
+		"map[example.com/revision:3]"
+	if events := tr.recordedEvents(); len(events) != 1 || events[0] != want {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
+
+func TestContextReference(t *testing.T) {
+	ctx := &Context{Scheme: newTestScheme(t)}
+	obj := newTestObject("referenced")
+	obj.UID = "uid-1"
+	obj.ResourceVersion = "7"
+
+	ref, err := ctx.Reference(obj)
+	if err != nil {
+		t.Fatalf("Reference() error = %v", err)
+	}
+	want := &corev1.ObjectReference{
+		Kind:            "testObject",
+		APIVersion:      "test.dominodatalab.com/v1",
+		Namespace:       "default",
+		Name:            "referenced",
+		UID:             "uid-1",
+		ResourceVersion: "7",
+	}
+	if !reflect.DeepEqual(ref, want) {
+		t.Errorf("Reference() = %+v, want %+v", ref, want)
+	}
+
+	if _, err = ctx.Reference(&corev1.ConfigMap{}); err == nil {
+		t.Error("Reference() succeeded for a type missing from the scheme")
+	}
+}
+
+func TestContextOriginal(t *testing.T) {
+	obj := newTestObject("original")
+	obj.Labels = map[string]string{"tier": "web"}
+	tr := newTestReconciler(t, obj)
+
+	var original, mutated *testObject
+	r := tr.build("original", func(r *Reconciler) {
+		r.Component("mutate", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Object.SetLabels(map[string]string{"tier": "api"})
+			ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+			ctx.Conditions.Flush()
+			return ctrl.Result{}, nil
+		}))
+		r.Component("inspect", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			original = ctx.Original.(*testObject).DeepCopyObject().(*testObject)
+			mutated = ctx.Object.(*testObject).DeepCopyObject().(*testObject)
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if original.Labels["tier"] != "web" || len(original.Status.Conditions) != 0 {
+		t.Errorf("original = %+v, want the pre-reconcile state", original)
+	}
+	if mutated.Labels["tier"] != "api" || len(mutated.Status.Conditions) != 1 {
+		t.Errorf("object = %+v, want the mutated state", mutated)
+	}
+}
+
+func TestContextPatchChildStatus(t *testing.T) {
+	child := newTestObject("child")
+	statusPatches := 0
+	tr := newTestReconcilerWithFuncs(t, statusPatchCounter(&statusPatches), child)
+	ctx := &Context{Context: context.Background(), Client: tr.client, fieldManager: "parent"}
+	key := client.ObjectKeyFromObject(child)
+
+	current := tr.get(key)
+	original := current.DeepCopyObject().(client.Object)
+	if err := ctx.PatchChildStatus(current, original); err != nil {
+		t.Fatalf("PatchChildStatus() error = %v", err)
+	}
+	if statusPatches != 0 {
+		t.Errorf("sent %d status patches for an unchanged status, want none", statusPatches)
+	}
+
+	current.Status.Conditions = []metav1.Condition{{
+		Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done", LastTransitionTime: metav1.Now(),
+	}}
+	if err := ctx.PatchChildStatus(current, original); err != nil {
+		t.Fatalf("PatchChildStatus() error = %v", err)
+	}
+	if statusPatches != 1 {
+		t.Errorf("sent %d status patches for a changed status, want 1", statusPatches)
+	}
+	if conditions := tr.get(key).Status.Conditions; len(conditions) != 1 || conditions[0].Type != "Ready" {
+		t.Errorf("stored conditions = %+v, want the patched status", conditions)
+	}
+}
+
+func TestContextResourceFor(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(testGroupVersion.WithKind("TestObject"), meta.RESTScopeNamespace)
+
+	ctx := &Context{RESTMapper: mapper}
+	gvr, err := ctx.ResourceFor(testGroupVersion.WithKind("TestObject"))
+	if err != nil {
+		t.Fatalf("ResourceFor() error = %v", err)
+	}
+	if want := testGroupVersion.WithResource("testobjects"); gvr != want {
+		t.Errorf("ResourceFor() = %v, want %v", gvr, want)
+	}
+
+	if _, err = ctx.ResourceFor(testGroupVersion.WithKind("Missing")); !meta.IsNoMatchError(err) {
+		t.Errorf("ResourceFor() error = %v, want a no match error", err)
+	}
+}
+
+func TestContextRESTMapperIsSet(t *testing.T) {
+	obj := newTestObject("rest-mapper")
+	tr := newTestReconciler(t, obj)
+
+	var mapper meta.RESTMapper
+	r := tr.build("rest-mapper", func(r *Reconciler) {
+		r.Component("mapper", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			mapper = ctx.RESTMapper
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if mapper == nil || mapper != tr.mgr.GetRESTMapper() {
+		t.Error("context RESTMapper is not the manager RESTMapper")
+	}
+}
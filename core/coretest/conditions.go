@@ -0,0 +1,54 @@
+// Package coretest provides helpers for testing components built on the core package.
+package coretest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dominodatalab/controller-util/core"
+)
+
+// AssertCondition fails the test unless obj carries a condition of the given type with the expected status and
+// reason. Conditions are read using core.ConditionObject.
+func AssertCondition(t testing.TB, obj client.Object, condType string, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+
+	condObj, ok := obj.(core.ConditionObject)
+	if !ok {
+		t.Errorf("%T does not implement core.ConditionObject", obj)
+		return
+	}
+
+	var conditions []metav1.Condition
+	if ptr := condObj.GetConditions(); ptr != nil {
+		conditions = *ptr
+	}
+
+	cond := core.FindStatusCondition(conditions, condType)
+	if cond == nil {
+		t.Errorf("condition %q not found on %s, present conditions:\n%s", condType, client.ObjectKeyFromObject(obj),
+			describeConditions(conditions))
+		return
+	}
+
+	if cond.Status != status || cond.Reason != reason {
+		t.Errorf("condition %q on %s does not match\n  want: status=%s reason=%s\n  got:  status=%s reason=%s message=%q",
+			condType, client.ObjectKeyFromObject(obj), status, reason, cond.Status, cond.Reason, cond.Message)
+	}
+}
+
+func describeConditions(conditions []metav1.Condition) string {
+	if len(conditions) == 0 {
+		return "  <none>"
+	}
+
+	lines := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		lines = append(lines, fmt.Sprintf("  %s: status=%s reason=%s", cond.Type, cond.Status, cond.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package coretest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// conditionObject is a minimal object implementing core.ConditionObject.
+type conditionObject struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Conditions []metav1.Condition
+}
+
+func (o *conditionObject) GetConditions() *[]metav1.Condition {
+	return &o.Conditions
+}
+
+func (o *conditionObject) DeepCopyObject() runtime.Object {
+	c := *o
+	o.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	c.Conditions = append([]metav1.Condition(nil), o.Conditions...)
+	return &c
+}
+
+// recordingT records failures instead of failing the test running the assertion.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCondition(t *testing.T) {
+	obj := &conditionObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "obj", Namespace: "default"},
+		Conditions: []metav1.Condition{
+			{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Done"},
+			{Type: "Degraded", Status: metav1.ConditionFalse, Reason: "Healthy"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		obj      client.Object
+		condType string
+		status   metav1.ConditionStatus
+		reason   string
+		want     []string
+	}{
+		{
+			name: "present", obj: obj, condType: "Ready", status: metav1.ConditionTrue, reason: "Done",
+		},
+		{
+			name: "absent", obj: obj, condType: "Available", status: metav1.ConditionTrue, reason: "Done",
+			want: []string{`condition "Available" not found on default/obj`, "Ready: status=True reason=Done"},
+		},
+		{
+			name: "status mismatch", obj: obj, condType: "Ready", status: metav1.ConditionFalse, reason: "Done",
+			want: []string{"want: status=False reason=Done", "got:  status=True reason=Done"},
+		},
+		{
+			name: "reason mismatch", obj: obj, condType: "Degraded", status: metav1.ConditionFalse, reason: "Broken",
+			want: []string{"want: status=False reason=Broken", "got:  status=False reason=Healthy"},
+		},
+		{
+			name: "no conditions", obj: &corev1.ConfigMap{}, condType: "Ready", status: metav1.ConditionTrue, reason: "Done",
+			want: []string{"does not implement core.ConditionObject"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			AssertCondition(rt, tc.obj, tc.condType, tc.status, tc.reason)
+
+			if len(tc.want) == 0 {
+				if len(rt.errors) != 0 {
+					t.Errorf("AssertCondition() failed unexpectedly: %v", rt.errors)
+				}
+				return
+			}
+			if len(rt.errors) != 1 {
+				t.Fatalf("AssertCondition() reported %d failures, want 1: %v", len(rt.errors), rt.errors)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(rt.errors[0], want) {
+					t.Errorf("failure message %q does not contain %q", rt.errors[0], want)
+				}
+			}
+		})
+	}
+}
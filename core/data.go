@@ -0,0 +1,37 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var dataKeySeq uint64
+
+// DataKey binds a ContextData entry to a value type. Every key created with NewDataKey has a distinct identity, so two
+// keys never collide even when they share a name.
+type DataKey[T any] struct {
+	name string
+	id   string
+}
+
+func NewDataKey[T any](name string) *DataKey[T] {
+	return &DataKey[T]{
+		name: name,
+		id:   fmt.Sprintf("%s#%d", name, atomic.AddUint64(&dataKeySeq, 1)),
+	}
+}
+
+func (k *DataKey[T]) String() string {
+	return k.name
+}
+
+// SetData stores v in data under key.
+func SetData[T any](data ContextData, key *DataKey[T], v T) {
+	data[key.id] = v
+}
+
+// GetData returns the value stored in data under key and whether it was present.
+func GetData[T any](data ContextData, key *DataKey[T]) (T, bool) {
+	v, ok := data[key.id].(T)
+	return v, ok
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDataKeysDoNotCollide(t *testing.T) {
+	data := ContextData{"replicas": "plain"}
+	count := NewDataKey[int]("replicas")
+	label := NewDataKey[string]("replicas")
+	other := NewDataKey[int]("replicas")
+
+	if _, ok := GetData(data, count); ok {
+		t.Errorf("GetData() found a value set under the plain string key")
+	}
+
+	SetData(data, count, 3)
+	SetData(data, label, "three")
+
+	if v, ok := GetData(data, count); !ok || v != 3 {
+		t.Errorf("GetData(int key) = %v, %t, want 3", v, ok)
+	}
+	if v, ok := GetData(data, label); !ok || v != "three" {
+		t.Errorf("GetData(string key) = %q, %t, want three", v, ok)
+	}
+	if v, ok := GetData(data, other); ok || v != 0 {
+		t.Errorf("GetData() of another key with the same name and type = %v, %t, want no value", v, ok)
+	}
+	if data["replicas"] != "plain" {
+		t.Errorf("typed keys overwrote the plain string key: %v", data["replicas"])
+	}
+	if count.String() != "replicas" {
+		t.Errorf("String() = %q, want the key name", count.String())
+	}
+}
+
+func TestContextDataProvider(t *testing.T) {
+	first, second := newTestObject("first"), newTestObject("second")
+	tr := newTestReconciler(t, first, second)
+
+	var seen []interface{}
+	r := tr.build("data-provider", func(r *Reconciler) {
+		r.WithContextData("static", "shared")
+		r.WithContextDataProvider("tenant", func(_ context.Context, obj client.Object) (interface{}, error) {
+			return "tenant-" + obj.GetName(), nil
+		})
+		r.Component("read", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			seen = append(seen, ctx.Data["tenant"], ctx.Data["static"])
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for _, obj := range []*testObject{first, second} {
+		if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	want := []interface{}{"tenant-first", "shared", "tenant-second", "shared"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("context data = %v, want %v", seen, want)
+	}
+}
+
+func TestContextDataProviderError(t *testing.T) {
+	obj := newTestObject("data-error")
+	tr := newTestReconciler(t, obj)
+
+	ran := false
+	r := tr.build("data-error", func(r *Reconciler) {
+		r.WithContextDataProvider("tenant", func(context.Context, client.Object) (interface{}, error) {
+			return nil, errors.New("tenant config missing")
+		})
+		r.Component("read", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran = true
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	_, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+	if err == nil || !strings.Contains(err.Error(), "cannot compute context data tenant: tenant config missing") {
+		t.Errorf("Reconcile() error = %v, want the provider error", err)
+	}
+	if ran {
+		t.Error("component ran although the context data could not be computed")
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDryRunReportsDiffsWithoutPatching(t *testing.T) {
+	obj := newTestObject("dry-run")
+	patches := 0
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patches++
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			patches++
+			return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	// a fixed transition time keeps the status diff stable
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return clock }
+
+	diffs := map[string]string{}
+	r := tr.build("dry-run", func(r *Reconciler) {
+		r.WithDryRun().WithDiffReporter(func(kind string, diff []byte) {
+			diffs[kind] = string(diff)
+		})
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(ctx *Context) (ctrl.Result, error) {
+				ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	want := map[string]string{
+		"metadata": `{"metadata":{"labels":{"example.com/labeled":"true"}}}`,
+		"status": `{"metadata":{"finalizers":["dry-run.test.dominodatalab.com/cleanup"],"labels":{"example.com/labeled":"true"}},` +
+			`"status":{"conditions":[{"lastTransitionTime":"2024-01-01T12:00:00Z","message":"component is ready",` +
+			`"reason":"Done","status":"True","type":"Ready"}]}}`,
+	}
+	for kind, diff := range want {
+		if diffs[kind] != diff {
+			t.Errorf("%s diff = %s, want %s", kind, diffs[kind], diff)
+		}
+	}
+	// the finalizer patch carries the resourceVersion as optimistic lock
+	if !strings.Contains(diffs["finalizers"], `"finalizers":["dry-run.test.dominodatalab.com/cleanup"]`) {
+		t.Errorf("finalizers diff = %s, want the added finalizer", diffs["finalizers"])
+	}
+	if patches != 0 {
+		t.Errorf("dry run sent %d patches, want none", patches)
+	}
+
+	stored := tr.get(key)
+	if len(stored.Labels) != 0 || len(stored.Finalizers) != 0 || len(stored.Status.Conditions) != 0 {
+		t.Errorf("dry run changed the stored object: %+v", stored)
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// TerminalErrorConditionType is set on objects that support conditions while reconciles fail with terminal errors.
+	TerminalErrorConditionType = "TerminalError"
+	// TerminalErrorReason is the reason of the TerminalError condition and of the Warning event recorded with it.
+	TerminalErrorReason = "TerminalError"
+)
+
+// ErrorClass determines how a failed reconcile is retried.
+type ErrorClass int
+
+const (
+	// ErrorClassDefault errors are retried with the controller's rate limited backoff.
+	ErrorClassDefault ErrorClass = iota
+	// ErrorClassTransient errors are retried with the controller's rate limited backoff like default errors, but do
+	// not count towards the failures tracked by WithStalledAfter.
+	ErrorClassTransient
+	// ErrorClassTerminal errors are not retried until the object changes. They are reported with a Warning event and
+	// the TerminalError condition.
+	ErrorClassTerminal
+)
+
+// ErrorClassifier assigns an ErrorClass to an error returned during reconcile.
+type ErrorClassifier func(error) ErrorClass
+
+// DefaultErrorClassifier treats conflicts, timeouts and throttling as transient, and invalid or forbidden requests as
+// terminal.
+func DefaultErrorClassifier(err error) ErrorClass {
+	switch {
+	case apierrors.IsConflict(err), apierrors.IsServerTimeout(err), apierrors.IsTimeout(err), apierrors.IsTooManyRequests(err):
+		return ErrorClassTransient
+	case apierrors.IsInvalid(err), apierrors.IsForbidden(err):
+		return ErrorClassTerminal
+	}
+	return ErrorClassDefault
+}
+
+// classifyErrors returns the class shared by all errs, or ErrorClassDefault when they differ.
+func (r *Reconciler) classifyErrors(errs []error) ErrorClass {
+	if r.errorClassifier == nil || len(errs) == 0 {
+		return ErrorClassDefault
+	}
+
+	class := r.errorClassifier(errs[0])
+	for _, err := range errs[1:] {
+		if r.errorClassifier(err) != class {
+			return ErrorClassDefault
+		}
+	}
+	return class
+}
+
+// reportTerminalErrors sets the TerminalError condition while a reconcile fails with terminal errors and removes it
+// once the reconcile no longer does.
+func (r *Reconciler) reportTerminalErrors(ctx *Context, errs []error, class ErrorClass) {
+	if class != ErrorClassTerminal || len(errs) == 0 {
+		if conditions := r.conditions(ctx.Object); conditions != nil {
+			RemoveStatusCondition(conditions, TerminalErrorConditionType)
+		}
+		return
+	}
+
+	message := utilerrors.NewAggregate(errs).Error()
+	r.recorder.Event(ctx.Object, corev1.EventTypeWarning, TerminalErrorReason, message)
+	ctx.Conditions.SetTrue(TerminalErrorConditionType, TerminalErrorReason, message)
+	ctx.Conditions.Flush()
+}
+
+// classifiedResult marks terminal errors so that the controller does not retry them.
+func classifiedResult(res ctrl.Result, class ErrorClass, err error) (ctrl.Result, error) {
+	if err != nil && class == ErrorClassTerminal {
+		return res, reconcile.TerminalError(err)
+	}
+	return res, err
+}
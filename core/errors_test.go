@@ -0,0 +1,379 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var testResource = schema.GroupResource{Group: testGroupVersion.Group, Resource: "testobjects"}
+
+// reconcileTerminalError matches errors marked with reconcile.TerminalError using errors.Is.
+var reconcileTerminalError = reconcile.TerminalError(nil)
+
+func TestDefaultErrorClassifier(t *testing.T) {
+	gk := schema.GroupKind{Group: testGroupVersion.Group, Kind: "testObject"}
+	cases := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "conflict", err: apierrors.NewConflict(testResource, "obj", errors.New("stale")), want: ErrorClassTransient},
+		{name: "server timeout", err: apierrors.NewServerTimeout(testResource, "get", 1), want: ErrorClassTransient},
+		{name: "timeout", err: apierrors.NewTimeoutError("slow", 1), want: ErrorClassTransient},
+		{name: "throttled", err: apierrors.NewTooManyRequests("busy", 1), want: ErrorClassTransient},
+		{name: "invalid", err: apierrors.NewInvalid(gk, "obj", nil), want: ErrorClassTerminal},
+		{name: "forbidden", err: apierrors.NewForbidden(testResource, "obj", errors.New("denied")), want: ErrorClassTerminal},
+		{name: "wrapped", err: fmt.Errorf("component %q: %w", "c", apierrors.NewInvalid(gk, "obj", nil)), want: ErrorClassTerminal},
+		{name: "other", err: errors.New("boom"), want: ErrorClassDefault},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DefaultErrorClassifier(tc.err); got != tc.want {
+				t.Errorf("DefaultErrorClassifier() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	conflict := apierrors.NewConflict(testResource, "obj", errors.New("stale"))
+	forbidden := apierrors.NewForbidden(testResource, "obj", errors.New("denied"))
+
+	r := &Reconciler{}
+	if got := r.classifyErrors([]error{forbidden}); got != ErrorClassDefault {
+		t.Errorf("classifyErrors() without classifier = %v, want default", got)
+	}
+
+	r.errorClassifier = DefaultErrorClassifier
+	cases := []struct {
+		name string
+		errs []error
+		want ErrorClass
+	}{
+		{name: "none", want: ErrorClassDefault},
+		{name: "all terminal", errs: []error{forbidden, forbidden}, want: ErrorClassTerminal},
+		{name: "all transient", errs: []error{conflict}, want: ErrorClassTransient},
+		{name: "mixed", errs: []error{conflict, forbidden}, want: ErrorClassDefault},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.classifyErrors(tc.errs); got != tc.want {
+				t.Errorf("classifyErrors() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// erroringComponent returns the next error of errs on each reconcile, and no error once they are exhausted.
+func erroringComponent(errs ...error) componentFunc {
+	return func(*Context) (ctrl.Result, error) {
+		if len(errs) == 0 {
+			return ctrl.Result{}, nil
+		}
+		err := errs[0]
+		errs = errs[1:]
+		return ctrl.Result{}, err
+	}
+}
+
+func TestReconcileReportsTerminalErrors(t *testing.T) {
+	obj := newTestObject("terminal")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	forbidden := apierrors.NewForbidden(testResource, "obj", errors.New("denied"))
+
+	r := tr.build("terminal", func(r *Reconciler) {
+		r.WithErrorClassifier(DefaultErrorClassifier).Component("fail", erroringComponent(forbidden))
+	})
+
+	_, err := tr.reconcile(r, key)
+	if !errors.Is(err, reconcileTerminalError) {
+		t.Errorf("Reconcile() error = %v, want a terminal error", err)
+	}
+	assertCondition(t, tr.get(key), TerminalErrorConditionType, metav1.ConditionTrue)
+	if events := tr.recordedEvents(); len(events) != 1 || !strings.HasPrefix(events[0], "Warning TerminalError") {
+		t.Errorf("events = %v, want a TerminalError warning", events)
+	}
+
+	if _, err = tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if cond := FindStatusCondition(tr.get(key).Status.Conditions, TerminalErrorConditionType); cond != nil {
+		t.Errorf("TerminalError condition not removed after a successful reconcile: %+v", cond)
+	}
+}
+
+func TestReconcileKeepsTransientErrorsOnRateLimiter(t *testing.T) {
+	obj := newTestObject("transient")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	conflict := apierrors.NewConflict(testResource, "obj", errors.New("stale"))
+
+	r := tr.build("transient", func(r *Reconciler) {
+		r.WithErrorClassifier(DefaultErrorClassifier).Component("fail", erroringComponent(conflict))
+	})
+
+	res, err := tr.reconcile(r, key)
+	if err == nil || !strings.Contains(err.Error(), "stale") || errors.Is(err, reconcileTerminalError) {
+		t.Errorf("Reconcile() error = %v, want the conflict returned as a regular error", err)
+	}
+	if !res.IsZero() {
+		t.Errorf("Reconcile() result = %+v, want the rate limiter to schedule the retry", res)
+	}
+}
+
+func TestTransientErrorsDoNotResetStalledCount(t *testing.T) {
+	obj := newTestObject("stalled-transient")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	boom := errors.New("boom")
+	conflict := apierrors.NewConflict(testResource, "obj", errors.New("stale"))
+
+	r := tr.build("stalled-transient", func(r *Reconciler) {
+		r.WithErrorClassifier(DefaultErrorClassifier).
+			WithStalledAfter(3).
+			Component("fail", erroringComponent(boom, boom, conflict, boom))
+	})
+
+	for i := 0; i < 4; i++ {
+		_, _ = tr.reconcile(r, key)
+	}
+
+	if attempts, _ := r.failures.Load(key); attempts != 3 {
+		t.Errorf("failure count = %v, want 3", attempts)
+	}
+	assertCondition(t, tr.get(key), StalledConditionType, metav1.ConditionTrue)
+}
+
+// assertCondition fails the test unless obj carries a condition of the given type and status.
+func assertCondition(t *testing.T, obj *testObject, condType string, status metav1.ConditionStatus) {
+	t.Helper()
+
+	cond := FindStatusCondition(obj.Status.Conditions, condType)
+	if cond == nil {
+		t.Errorf("condition %s not found in %+v", condType, obj.Status.Conditions)
+		return
+	}
+	if cond.Status != status {
+		t.Errorf("condition %s status = %s, want %s", condType, cond.Status, status)
+	}
+}
+
+func TestStalledAfterConsecutiveFailures(t *testing.T) {
+	obj := newTestObject("stalled")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	boom := errors.New("boom")
+
+	r := tr.build("stalled", func(r *Reconciler) {
+		r.WithStalledAfter(2).Component("fail", erroringComponent(boom, boom))
+	})
+
+	_, _ = tr.reconcile(r, key)
+	if cond := FindStatusCondition(tr.get(key).Status.Conditions, StalledConditionType); cond != nil {
+		t.Errorf("Stalled condition set after a single failure: %+v", cond)
+	}
+
+	_, _ = tr.reconcile(r, key)
+	assertCondition(t, tr.get(key), StalledConditionType, metav1.ConditionTrue)
+	if events := tr.recordedEvents(); len(events) != 1 || !strings.HasPrefix(events[0], "Warning Stalled") {
+		t.Errorf("events = %v, want a single Stalled warning", events)
+	}
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if cond := FindStatusCondition(tr.get(key).Status.Conditions, StalledConditionType); cond != nil {
+		t.Errorf("Stalled condition not removed after a successful reconcile: %+v", cond)
+	}
+	if _, ok := r.failures.Load(key); ok {
+		t.Errorf("failure count not reset after a successful reconcile")
+	}
+}
+
+func TestStalledCountSurvivesReconcilesWithoutComponents(t *testing.T) {
+	obj := newTestObject("stalled-invalid")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	boom := errors.New("boom")
+
+	invalid := false
+	r := tr.build("stalled-invalid", func(r *Reconciler) {
+		r.WithStalledAfter(2).
+			WithObjectValidator(func(client.Object) error {
+				if invalid {
+					return errors.New("invalid")
+				}
+				return nil
+			}).
+			Component("fail", erroringComponent(boom, boom))
+	})
+
+	_, _ = tr.reconcile(r, key)
+
+	// neither an invalid nor a paused object ran its components, hence the failure count is kept
+	invalid = true
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	invalid = false
+	paused := tr.get(key)
+	paused.Annotations = map[string]string{SkipReconcileAnnotation: "true"}
+	if err := tr.client.Update(context.Background(), paused); err != nil {
+		t.Fatalf("cannot pause object: %v", err)
+	}
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	paused = tr.get(key)
+	paused.Annotations = nil
+	if err := tr.client.Update(context.Background(), paused); err != nil {
+		t.Fatalf("cannot resume object: %v", err)
+	}
+
+	_, _ = tr.reconcile(r, key)
+	if attempts, _ := r.failures.Load(key); attempts != 2 {
+		t.Errorf("failure count = %v, want 2", attempts)
+	}
+	assertCondition(t, tr.get(key), StalledConditionType, metav1.ConditionTrue)
+}
+
+func TestCompletionEvents(t *testing.T) {
+	obj := newTestObject("completion")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+	boom := errors.New("boom")
+
+	build := func(name string, enabled bool) *Reconciler {
+		return tr.build(name, func(r *Reconciler) {
+			if enabled {
+				r.WithCompletionEvent()
+			}
+			r.Component("outcome", erroringComponent(nil, boom))
+		})
+	}
+
+	r := build("completion", true)
+	_, _ = tr.reconcile(r, key)
+	if events := tr.recordedEvents(); len(events) != 1 || events[0] != "Normal ReconcileSucceeded Reconciliation completed successfully" {
+		t.Errorf("events after an error-free reconcile = %v, want ReconcileSucceeded", events)
+	}
+	_, _ = tr.reconcile(r, key)
+	if events := tr.recordedEvents(); len(events) != 1 || !strings.HasPrefix(events[0], "Warning ReconcileFailed") ||
+		!strings.Contains(events[0], "boom") {
+		t.Errorf("events after a failed reconcile = %v, want ReconcileFailed", events)
+	}
+
+	r = build("completion-disabled", false)
+	_, _ = tr.reconcile(r, key)
+	_, _ = tr.reconcile(r, key)
+	if events := tr.recordedEvents(); len(events) != 0 {
+		t.Errorf("events without the option = %v, want none", events)
+	}
+}
+
+func TestComponentErrorsAreAttributed(t *testing.T) {
+	obj := newTestObject("attributed")
+	tr := newTestReconciler(t, obj)
+
+	errQuota := errors.New("quota exceeded")
+	conflict := apierrors.NewConflict(testResource, "child", errors.New("stale"))
+	r := tr.build("attributed", func(r *Reconciler) {
+		r.Component("storage", erroringComponent(fmt.Errorf("cannot provision volume: %w", errQuota)))
+		r.Component("network", erroringComponent(conflict))
+	})
+
+	_, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+	if err == nil {
+		t.Fatal("Reconcile() succeeded, want the component errors")
+	}
+	for _, want := range []string{
+		`component "storage": cannot provision volume: quota exceeded`,
+		`component "network": ` + conflict.Error(),
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Reconcile() error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	if !errors.Is(err, errQuota) {
+		t.Errorf("errors.Is() did not find the wrapped sentinel in %q", err)
+	}
+	// aggregates only implement errors.Is, so errors.As is applied to each aggregated error
+	var statusErr *apierrors.StatusError
+	found := false
+	for _, e := range err.(utilerrors.Aggregate).Errors() {
+		if errors.As(e, &statusErr) {
+			found = apierrors.IsConflict(statusErr)
+		}
+	}
+	if !found {
+		t.Errorf("errors.As() did not find the api status error in %q", err)
+	}
+}
+
+// joinedError is the custom error produced by the aggregator under test.
+type joinedError struct {
+	errs []error
+}
+
+func (e joinedError) Error() string {
+	messages := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, " | ")
+}
+
+func TestErrorAggregator(t *testing.T) {
+	obj := newTestObject("aggregator")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	fail := true
+	calls := 0
+	r := tr.build("aggregator", func(r *Reconciler) {
+		r.WithErrorAggregator(func(errs []error) error {
+			calls++
+			return joinedError{errs: errs}
+		})
+		for _, name := range []string{"first", "second"} {
+			name := name
+			r.Component(name, componentFunc(func(*Context) (ctrl.Result, error) {
+				if fail {
+					return ctrl.Result{}, fmt.Errorf("%s failed", name)
+				}
+				return ctrl.Result{}, nil
+			}))
+		}
+	})
+
+	_, err := tr.reconcile(r, key)
+	var joined joinedError
+	if !errors.As(err, &joined) {
+		t.Fatalf("Reconcile() error = %#v, want the aggregator's error", err)
+	}
+	if len(joined.errs) != 2 || !strings.Contains(err.Error(), "first failed") || !strings.Contains(err.Error(), "second failed") {
+		t.Errorf("Reconcile() error = %q, want both component errors", err)
+	}
+
+	fail = false
+	if _, err = tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("aggregator called %d times, want it skipped without errors", calls)
+	}
+}
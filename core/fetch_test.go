@@ -0,0 +1,156 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileHandlesFetchErrors(t *testing.T) {
+	resource := schema.GroupResource{Group: testGroupVersion.Group, Resource: "testobjects"}
+
+	live := newTestObject("live")
+	deleting := newTestObject("deleting")
+	deleting.Finalizers = []string{"fetch.test.dominodatalab.com/cleanup"}
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	var getErr error
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if getErr != nil {
+				return getErr
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, live, deleting)
+
+	done := false
+	r := tr.build("fetch", func(r *Reconciler) {
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) {
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				return ctrl.Result{}, done, nil
+			},
+		})
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		getErr = apierrors.NewForbidden(resource, "live", nil)
+		defer func() { getErr = nil }()
+
+		_, err := tr.reconcile(r, client.ObjectKeyFromObject(live))
+		if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), "verify its RBAC permissions") {
+			t.Errorf("Reconcile() error = %v, want a forbidden error with an RBAC hint", err)
+		}
+		if events := tr.recordedEvents(); len(events) != 0 {
+			t.Errorf("events = %v, want none for an object not pending deletion", events)
+		}
+	})
+
+	t.Run("pending deletion", func(t *testing.T) {
+		key := client.ObjectKeyFromObject(deleting)
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+
+		getErr = apierrors.NewInternalError(context.DeadlineExceeded)
+		_, err := tr.reconcile(r, key)
+		getErr = nil
+		if !apierrors.IsInternalError(err) {
+			t.Errorf("Reconcile() error = %v, want the fetch error", err)
+		}
+		if events := tr.recordedEvents(); len(events) != 1 || !strings.HasPrefix(events[0], "Warning FetchFailed") {
+			t.Errorf("events = %v, want a single FetchFailed warning", events)
+		}
+	})
+
+	t.Run("not found during deletion", func(t *testing.T) {
+		key := client.ObjectKeyFromObject(deleting)
+		if _, ok := r.terminating.Load(key); !ok {
+			t.Fatal("object pending deletion is not tracked as terminating")
+		}
+
+		done = true
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Errorf("Reconcile() of a deleted object error = %v, want none", err)
+		}
+		if _, ok := r.terminating.Load(key); ok {
+			t.Errorf("deleted object is still tracked as terminating")
+		}
+		if events := tr.recordedEvents(); len(events) != 0 {
+			t.Errorf("events = %v, want none", events)
+		}
+	})
+}
+
+func TestReconcileGenerateNameObjectAfterDeletion(t *testing.T) {
+	writes := 0
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			writes++
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			writes++
+			return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+		},
+	})
+
+	obj := &testObject{ObjectMeta: metav1.ObjectMeta{GenerateName: "generated-", Namespace: "default"}}
+	if err := tr.client.Create(context.Background(), obj); err != nil {
+		t.Fatalf("cannot create object: %v", err)
+	}
+	key := client.ObjectKeyFromObject(obj)
+	if !strings.HasPrefix(key.Name, "generated-") {
+		t.Fatalf("object name = %q, want a generated name", key.Name)
+	}
+
+	var seen []client.Object
+	r := tr.build("generated", func(r *Reconciler) {
+		r.ReconcileNotFound()
+		r.Component("record", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			seen = append(seen, ctx.Object.DeepCopyObject().(client.Object))
+			ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := tr.client.Delete(context.Background(), tr.get(key)); err != nil {
+		t.Fatalf("cannot delete object: %v", err)
+	}
+
+	writes = 0
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() of the deleted object error = %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("sent %d patches for a deleted object, want none", writes)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("component ran %d times, want 2", len(seen))
+	}
+	synthesized := seen[1]
+	if client.ObjectKeyFromObject(synthesized) != key || synthesized.GetGenerateName() != "" {
+		t.Errorf("synthesized object = %s with generateName %q, want %s without generateName",
+			client.ObjectKeyFromObject(synthesized), synthesized.GetGenerateName(), key)
+	}
+	if err := tr.client.Get(context.Background(), key, &testObject{}); !apierrors.IsNotFound(err) {
+		t.Errorf("deleted object was recreated: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ContentHashAnnotation is the conventional pod template annotation used to roll workloads when referenced content
+// changes.
+const ContentHashAnnotation = "controller-util.dominodatalab.com/content-hash"
+
+// ContentHash fetches the referenced ConfigMap or Secret and returns a stable hash of its data.
+func (c *Context) ContentHash(obj client.Object) (string, error) {
+	if err := c.Client.Get(c, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return "", err
+	}
+	return ContentHash(obj)
+}
+
+// ContentHash returns a stable hash of the data held by a ConfigMap or Secret.
+func ContentHash(obj client.Object) (string, error) {
+	h := sha256.New()
+
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		for _, k := range sortedKeys(o.Data) {
+			fmt.Fprintf(h, "%s=%s;", k, o.Data[k])
+		}
+		for _, k := range sortedKeys(o.BinaryData) {
+			fmt.Fprintf(h, "%s=%x;", k, o.BinaryData[k])
+		}
+	case *corev1.Secret:
+		for _, k := range sortedKeys(o.Data) {
+			fmt.Fprintf(h, "%s=%x;", k, o.Data[k])
+		}
+		for _, k := range sortedKeys(o.StringData) {
+			fmt.Fprintf(h, "%s=%x;", k, []byte(o.StringData[k]))
+		}
+	default:
+		return "", fmt.Errorf("cannot compute content hash for %T", obj)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// StampRevision records a hash of the reconcile object's spec in the annotation key and returns it. The annotation is
+// only changed when the spec changed and is persisted with the metadata patch.
+func (c *Context) StampRevision(key string) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c.Object)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert %T to unstructured: %w", c.Object, err)
+	}
+	spec, err := json.Marshal(content["spec"])
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal spec: %w", err)
+	}
+
+	sum := sha256.Sum256(spec)
+	revision := hex.EncodeToString(sum[:])[:16]
+
+	annotations := c.Object.GetAnnotations()
+	if annotations[key] != revision {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = revision
+		c.Object.SetAnnotations(annotations)
+	}
+
+	return revision, nil
+}
+
+// WatchesContent enqueues the requests returned by mapFn whenever the content of a watched ConfigMap or Secret
+// changes. Updates that leave the content hash unchanged are ignored.
+func (r *Reconciler) WatchesContent(obj client.Object, mapFn handler.MapFunc) *Reconciler {
+	r.controllerBuilder.Watches(obj, handler.EnqueueRequestsFromMapFunc(mapFn), builder.WithPredicates(contentChangedPredicate()))
+	r.watched = append(r.watched, obj)
+	return r
+}
+
+func contentChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(evt event.UpdateEvent) bool {
+			oldHash, err := ContentHash(evt.ObjectOld)
+			if err != nil {
+				return true
+			}
+			newHash, err := ContentHash(evt.ObjectNew)
+			if err != nil {
+				return true
+			}
+			return oldHash != newHash
+		},
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestContentHash(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"a": "1", "b": "2"},
+	}
+
+	base, err := ContentHash(cm)
+	if err != nil {
+		t.Fatalf("ContentHash() error = %v", err)
+	}
+
+	relabeled := cm.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "data"}
+	relabeled.ResourceVersion = "2"
+	if got, _ := ContentHash(relabeled); got != base {
+		t.Errorf("hash changed after a metadata-only update: %s != %s", got, base)
+	}
+
+	changed := cm.DeepCopy()
+	changed.Data["b"] = "3"
+	if got, _ := ContentHash(changed); got == base {
+		t.Error("hash did not change with the data")
+	}
+
+	secret := &corev1.Secret{Data: map[string][]byte{"token": []byte("one")}}
+	secretHash, err := ContentHash(secret)
+	if err != nil {
+		t.Fatalf("ContentHash() error = %v", err)
+	}
+	secret.Data["token"] = []byte("two")
+	if got, _ := ContentHash(secret); got == secretHash {
+		t.Error("secret hash did not change with the data")
+	}
+
+	if _, err = ContentHash(&corev1.Pod{}); err == nil {
+		t.Error("ContentHash() succeeded for an unsupported kind")
+	}
+}
+
+func TestContextContentHash(t *testing.T) {
+	stored := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"a": "1"},
+	}
+	ctx := &Context{Context: context.Background(), Client: fake.NewClientBuilder().WithObjects(stored).Build()}
+
+	got, err := ctx.ContentHash(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("ContentHash() error = %v", err)
+	}
+	if want, _ := ContentHash(stored); got != want {
+		t.Errorf("ContentHash() = %s, want the hash of the stored data %s", got, want)
+	}
+}
+
+func TestContentChangedPredicate(t *testing.T) {
+	old := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("one")},
+	}
+
+	relabeled := old.DeepCopy()
+	relabeled.Labels = map[string]string{"rotated": "false"}
+	rotated := old.DeepCopy()
+	rotated.Data["token"] = []byte("two")
+
+	cases := []struct {
+		name string
+		new  client.Object
+		want bool
+	}{
+		{name: "metadata only", new: relabeled, want: false},
+		{name: "data changed", new: rotated, want: true},
+	}
+
+	pred := contentChangedPredicate()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: tc.new}); got != tc.want {
+				t.Errorf("Update() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+	if !pred.Create(event.CreateEvent{Object: old}) {
+		t.Error("Create() filtered a new object")
+	}
+}
+
+func TestWatchesContent(t *testing.T) {
+	tr := newTestReconciler(t)
+	r := tr.build("content", func(r *Reconciler) {
+		r.WithSchemeBuilder(corev1.AddToScheme)
+		r.WatchesContent(&corev1.ConfigMap{}, func(context.Context, client.Object) []reconcile.Request {
+			return nil
+		})
+	})
+
+	want := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	for _, gvk := range r.WatchedKinds() {
+		if gvk == want {
+			return
+		}
+	}
+	t.Errorf("WatchedKinds() = %v, want %v", r.WatchedKinds(), want)
+}
+
+func TestContextStampRevision(t *testing.T) {
+	obj := newTestObject("revision")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	const annotation = "example.com/revision"
+	var revisions []string
+	r := tr.build("revision", func(r *Reconciler) {
+		r.Component("stamp", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			revision, err := ctx.StampRevision(annotation)
+			revisions = append(revisions, revision)
+			return ctrl.Result{}, err
+		}))
+	})
+
+	update := func(mutate func(*testObject)) {
+		stored := tr.get(key)
+		mutate(stored)
+		if err := tr.client.Update(context.Background(), stored); err != nil {
+			t.Fatalf("cannot update object: %v", err)
+		}
+	}
+
+	steps := []struct {
+		name    string
+		before  func()
+		changed bool
+	}{
+		{name: "first reconcile", changed: true},
+		{name: "unchanged", changed: false},
+		{
+			name: "metadata change",
+			before: func() {
+				update(func(obj *testObject) { obj.Labels = map[string]string{"example.com/labeled": "true"} })
+			},
+			changed: false,
+		},
+		{
+			name:    "spec change",
+			before:  func() { update(func(obj *testObject) { obj.Spec.Replicas = 3 }) },
+			changed: true,
+		},
+	}
+
+	previous := ""
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("%s: Reconcile() error = %v", step.name, err)
+		}
+
+		revision := revisions[len(revisions)-1]
+		if len(revision) != 16 {
+			t.Errorf("%s: revision = %q, want a 16 character hash", step.name, revision)
+		}
+		if changed := revision != previous; changed != step.changed {
+			t.Errorf("%s: revision changed = %t, want %t", step.name, changed, step.changed)
+		}
+		if got := tr.get(key).Annotations[annotation]; got != revision {
+			t.Errorf("%s: annotation = %q, want %q", step.name, got, revision)
+		}
+		previous = revision
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// TestWithIndex requires the envtest binaries, see https://book.kubebuilder.io/reference/envtest.html.
+func TestWithIndex(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	env := &envtest.Environment{}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("cannot start test environment: %v", err)
+	}
+	defer func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("cannot stop test environment: %v", err)
+		}
+	}()
+
+	scheme := runtime.NewScheme()
+	if err = clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot build scheme: %v", err)
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: scheme, Metrics: metricsserver.Options{BindAddress: "0"}})
+	if err != nil {
+		t.Fatalf("cannot create manager: %v", err)
+	}
+
+	const field = "data.owner"
+	err = NewReconciler(mgr).
+		For(&corev1.ConfigMap{}).
+		Named("index").
+		WithIndex(nil, field, func(obj client.Object) []string {
+			return []string{obj.(*corev1.ConfigMap).Data["owner"]}
+		}).
+		Complete()
+	if err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager stopped: %v", err)
+		}
+	}()
+	if !mgr.GetCache().WaitForCacheSync(ctx) {
+		t.Fatal("cache did not sync")
+	}
+
+	for name, owner := range map[string]string{"first": "a", "second": "a", "third": "b"} {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string]string{"owner": owner},
+		}
+		if err = mgr.GetClient().Create(ctx, cm); err != nil {
+			t.Fatalf("cannot create configmap %s: %v", name, err)
+		}
+	}
+
+	list := &corev1.ConfigMapList{}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if err = mgr.GetClient().List(ctx, list, client.InNamespace("default"), client.MatchingFields{field: "a"}); err != nil {
+			t.Fatalf("cannot list by index: %v", err)
+		}
+		if len(list.Items) == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if len(list.Items) != 2 {
+		t.Errorf("listed %d configmaps by index, want 2", len(list.Items))
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileLogsObjectGVK(t *testing.T) {
+	obj := newTestObject("gvk")
+	tr := newTestReconciler(t, obj)
+
+	for _, tc := range []struct {
+		name   string
+		logGVK bool
+	}{
+		{name: "without gvk"},
+		{name: "with gvk", logGVK: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tr.build("gvk", func(r *Reconciler) {
+				if tc.logGVK {
+					r.WithObjectGVKLogging()
+				}
+				r.Component("log", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Log.Info("component message")
+					return ctrl.Result{}, nil
+				}))
+			})
+			log, lines := captureLogs()
+			r.log = log
+
+			if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			want := `"gvk"="test.dominodatalab.com/v1, Kind=testObject"`
+			var found bool
+			for _, line := range *lines {
+				if strings.Contains(line, "component message") {
+					found = true
+					if got := strings.Contains(line, want); got != tc.logGVK {
+						t.Errorf("component log line %q carries gvk = %t, want %t", line, got, tc.logGVK)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("component message not logged, got %v", *lines)
+			}
+		})
+	}
+}
+
+func TestReconcileUsesLogConstructor(t *testing.T) {
+	first, second := newTestObject("first"), newTestObject("second")
+	tr := newTestReconciler(t, first, second)
+
+	log, lines := captureLogs()
+	var requests []string
+	r := tr.build("log-constructor", func(r *Reconciler) {
+		r.WithLogConstructor(func(req *reconcile.Request) logr.Logger {
+			if req != nil {
+				requests = append(requests, req.Name)
+			}
+			return log.WithValues("source", "constructor")
+		})
+		r.Component("log", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Log.Info("component ran")
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for _, obj := range []*testObject{first, second} {
+		if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("constructor invoked for %v, want %v", requests, want)
+	}
+	found := false
+	for _, line := range *lines {
+		if strings.Contains(line, "component ran") {
+			found = true
+			if !strings.Contains(line, `"source"="constructor"`) {
+				t.Errorf("component log %q not derived from the constructed logger", line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("log = %q, want the component lines", *lines)
+	}
+}
+
+func TestQuietReconcileLogs(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		t.Run(fmt.Sprintf("quiet=%t", quiet), func(t *testing.T) {
+			obj := newTestObject("quiet-logs")
+			tr := newTestReconciler(t, obj)
+
+			r := tr.build("quiet-logs", func(r *Reconciler) {
+				if quiet {
+					r.WithQuietReconcileLogs()
+				}
+				r.Component("log", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Log.Info("component message")
+					return ctrl.Result{}, nil
+				}))
+			})
+			log, lines := captureLogs()
+			r.log = log
+
+			if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			bracketLevel := `"level"=0`
+			if quiet {
+				bracketLevel = `"level"=1`
+			}
+			levels := map[string]string{
+				`"msg"="Starting reconcile"`:      bracketLevel,
+				`"msg"="Reconciliation complete"`: bracketLevel,
+				`"msg"="component message"`:       `"level"=0`,
+			}
+			for msg, level := range levels {
+				var found bool
+				for _, line := range *lines {
+					if !strings.Contains(line, msg) {
+						continue
+					}
+					found = true
+					if !strings.Contains(line, level) {
+						t.Errorf("log line %s, want %s", line, level)
+					}
+				}
+				if !found {
+					t.Errorf("no log line with %s in %v", msg, *lines)
+				}
+			}
+		})
+	}
+}
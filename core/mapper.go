@@ -0,0 +1,42 @@
+package core
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+// EnqueueOwnersByLabel returns a handler.MapFunc that enqueues the owning instance of an object labeled using the
+// provider's standard labels. Objects labeled for a different application, or without an instance label, are ignored.
+// When the provider uses WithInstanceFunc, the instance label no longer names the owner, so the owner is resolved using
+// the object's controller reference instead and objects without one are ignored.
+func EnqueueOwnersByLabel(provider *metadata.Provider) handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		labels := obj.GetLabels()
+		if labels[metadata.ApplicationNameLabelKey] != provider.Application() {
+			return nil
+		}
+
+		instance, ok := labels[metadata.ApplicationInstanceLabelKey]
+		if !ok || instance == "" {
+			return nil
+		}
+		if provider.HasInstanceFunc() {
+			owner := metav1.GetControllerOf(obj)
+			if owner == nil {
+				return nil
+			}
+			instance = owner.Name
+		}
+
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: instance}},
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/dominodatalab/controller-util/metadata"
+)
+
+func TestEnqueueOwnersByLabel(t *testing.T) {
+	provider := metadata.NewProvider("app")
+	owner := newTestObject("instance")
+	standard := provider.StandardLabels(owner, "server", nil)
+
+	cases := []struct {
+		name   string
+		labels map[string]string
+		want   []reconcile.Request
+	}{
+		{
+			name:   "standard labels",
+			labels: standard,
+			want:   []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "instance"}}},
+		},
+		{name: "unlabeled"},
+		{
+			name:   "other application",
+			labels: metadata.NewProvider("other").StandardLabels(owner, "server", nil),
+		},
+		{
+			name: "missing instance",
+			labels: map[string]string{
+				metadata.ApplicationNameLabelKey: "app",
+			},
+		},
+		{
+			name: "empty instance",
+			labels: map[string]string{
+				metadata.ApplicationNameLabelKey:     "app",
+				metadata.ApplicationInstanceLabelKey: "",
+			},
+		},
+	}
+
+	mapFn := EnqueueOwnersByLabel(provider)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default", Labels: tc.labels}}
+			if got := mapFn(context.Background(), obj); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("EnqueueOwnersByLabel() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueOwnersByLabelWithInstanceFunc(t *testing.T) {
+	provider := metadata.NewProvider("app", metadata.WithInstanceFunc(func(client.Object) string { return "fixed" }))
+	owner := newTestObject("instance")
+	owner.UID = "owner-uid"
+	labels := provider.StandardLabels(owner, "server", nil)
+
+	controlled := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default", Labels: labels}}
+	if err := controllerutil.SetControllerReference(owner, controlled, newTestScheme(t)); err != nil {
+		t.Fatal(err)
+	}
+	orphan := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default", Labels: labels}}
+
+	mapFn := EnqueueOwnersByLabel(provider)
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "instance"}}}
+	if got := mapFn(context.Background(), controlled); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnqueueOwnersByLabel() = %v, want the controller %v", got, want)
+	}
+	if got := mapFn(context.Background(), orphan); got != nil {
+		t.Errorf("EnqueueOwnersByLabel() = %v, want objects without a controller ignored", got)
+	}
+}
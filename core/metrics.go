@@ -0,0 +1,91 @@
+package core
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var objectsFinalizing = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "controller_util_objects_finalizing",
+		Help: "Number of objects with a deletion timestamp currently handled by the controller.",
+	},
+	[]string{"controller", "namespace"},
+)
+
+var conditionStatus = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "controller_util_condition_status",
+		Help: "Number of objects handled by the controller by condition type and status.",
+	},
+	[]string{"controller", "type", "status"},
+)
+
+var reconcileSkipped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "controller_util_reconcile_skipped_total",
+		Help: "Number of reconciles skipped or filtered out by the controller by reason.",
+	},
+	[]string{"controller", "reason"},
+)
+
+// skipReason is the reason label of the reconcile skipped metric.
+type skipReason string
+
+const (
+	skipReasonFilter              skipReason = "filter"
+	skipReasonAnnotation          skipReason = "annotation"
+	skipReasonPausedField         skipReason = "paused_field"
+	skipReasonPredicate           skipReason = "skip_predicate"
+	skipReasonNamespaceAnnotation skipReason = "namespace_annotation"
+)
+
+func init() {
+	metrics.Registry.MustRegister(objectsFinalizing, conditionStatus, reconcileSkipped)
+}
+
+// recordSkip counts a skipped reconcile.
+func (r *Reconciler) recordSkip(reason skipReason) {
+	reconcileSkipped.WithLabelValues(r.name, string(reason)).Inc()
+}
+
+// recordConditionMetrics replaces the condition states previously recorded for an object with its current ones.
+func (r *Reconciler) recordConditionMetrics(key types.NamespacedName, conditions []metav1.Condition) {
+	current := make(map[string]metav1.ConditionStatus, len(conditions))
+	for _, cond := range conditions {
+		current[cond.Type] = cond.Status
+	}
+
+	r.forgetConditionMetrics(key)
+	for condType, status := range current {
+		conditionStatus.WithLabelValues(r.name, condType, string(status)).Inc()
+	}
+	r.conditionStates.Store(key, current)
+}
+
+// forgetConditionMetrics removes the condition states recorded for an object.
+func (r *Reconciler) forgetConditionMetrics(key types.NamespacedName) {
+	previous, loaded := r.conditionStates.LoadAndDelete(key)
+	if !loaded {
+		return
+	}
+	for condType, status := range previous.(map[string]metav1.ConditionStatus) {
+		conditionStatus.WithLabelValues(r.name, condType, string(status)).Dec()
+	}
+}
+
+// markTerminating tracks an object observed with a deletion timestamp.
+func (r *Reconciler) markTerminating(key types.NamespacedName) {
+	if _, loaded := r.terminating.LoadOrStore(key, struct{}{}); !loaded {
+		objectsFinalizing.WithLabelValues(r.name, key.Namespace).Inc()
+	}
+}
+
+// clearTerminating stops tracking an object once it is gone or no longer being deleted.
+func (r *Reconciler) clearTerminating(key types.NamespacedName) {
+	if _, loaded := r.terminating.LoadAndDelete(key); loaded {
+		objectsFinalizing.WithLabelValues(r.name, key.Namespace).Dec()
+	}
+}
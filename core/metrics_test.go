@@ -0,0 +1,169 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTerminatingGauge(t *testing.T) {
+	obj := newTestObject("terminating")
+	obj.Finalizers = []string{"terminating-metric.test.dominodatalab.com/cleanup"}
+	obj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	done := false
+	r := tr.build("terminating-metric", func(r *Reconciler) {
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) {
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				return ctrl.Result{}, done, nil
+			},
+		})
+	})
+	gauge := objectsFinalizing.WithLabelValues("terminating-metric", "default")
+
+	for i := 0; i < 2; i++ {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if v := testutil.ToFloat64(gauge); v != 1 {
+			t.Errorf("reconcile %d: finalizing gauge = %v while the finalizer remains, want 1", i+1, v)
+		}
+	}
+
+	done = true
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if v := testutil.ToFloat64(gauge); v != 0 {
+		t.Errorf("finalizing gauge = %v after the finalizer was removed, want 0", v)
+	}
+}
+
+func TestConditionMetrics(t *testing.T) {
+	obj := newTestObject("condition-metric")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	ready := false
+	r := tr.build("condition-metric", func(r *Reconciler) {
+		r.WithConditionMetrics()
+		r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			if ready {
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+			} else {
+				ctx.Conditions.SetFalse("Ready", "Pending", "component is not ready")
+			}
+			return ctrl.Result{}, nil
+		}))
+	})
+	// the gauges are global, so only the change caused by this reconciler is asserted
+	readyTrue := conditionStatus.WithLabelValues("condition-metric", "Ready", string(metav1.ConditionTrue))
+	readyFalse := conditionStatus.WithLabelValues("condition-metric", "Ready", string(metav1.ConditionFalse))
+	baseTrue, baseFalse := testutil.ToFloat64(readyTrue), testutil.ToFloat64(readyFalse)
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if vt, vf := testutil.ToFloat64(readyTrue)-baseTrue, testutil.ToFloat64(readyFalse)-baseFalse; vt != 0 || vf != 1 {
+		t.Errorf("Ready=True gauge = %+v, Ready=False gauge = %+v, want +0 and +1", vt, vf)
+	}
+
+	ready = true
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if vt, vf := testutil.ToFloat64(readyTrue)-baseTrue, testutil.ToFloat64(readyFalse)-baseFalse; vt != 1 || vf != 0 {
+		t.Errorf("Ready=True gauge = %+v, Ready=False gauge = %+v after the flip, want +1 and +0", vt, vf)
+	}
+}
+
+func TestConditionMetricsWithoutConditions(t *testing.T) {
+	obj := &bareObject{ObjectMeta: metav1.ObjectMeta{Name: "bare-metric", Namespace: "default"}}
+	tr := newTestReconciler(t, obj)
+
+	r := NewReconciler(tr.mgr).For(&bareObject{}).Named("bare-metric").WithConditionMetrics()
+	r.client = tr.client
+	r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+		ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+		return ctrl.Result{}, nil
+	}))
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if _, ok := r.conditionStates.Load(client.ObjectKeyFromObject(obj)); ok {
+		t.Error("condition states recorded for a type without conditions")
+	}
+	if v := testutil.ToFloat64(conditionStatus.WithLabelValues("bare-metric", "Ready", string(metav1.ConditionTrue))); v != 0 {
+		t.Errorf("Ready=True gauge = %v for a type without conditions, want 0", v)
+	}
+}
+
+func TestReconcileSkippedMetric(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*testObject)
+		reason skipReason
+	}{
+		{name: "reconciled"},
+		{
+			name:   "annotation",
+			mutate: func(obj *testObject) { obj.Annotations = map[string]string{SkipReconcileAnnotation: "true"} },
+			reason: skipReasonAnnotation,
+		},
+		{name: "paused field", mutate: func(obj *testObject) { obj.Spec.Paused = true }, reason: skipReasonPausedField},
+		{
+			name:   "filter",
+			mutate: func(obj *testObject) { obj.Labels = map[string]string{"example.com/ignored": "true"} },
+			reason: skipReasonFilter,
+		},
+	}
+
+	reasons := []skipReason{skipReasonFilter, skipReasonAnnotation, skipReasonPausedField}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("skip-metric")
+			if tc.mutate != nil {
+				tc.mutate(obj)
+			}
+			tr := newTestReconciler(t, obj)
+			r := tr.build("skip-metric", func(r *Reconciler) {
+				r.WithPausedField(func(obj client.Object) bool { return obj.(*testObject).Spec.Paused })
+				r.WithReconcileFilter(func(obj client.Object) bool { return obj.GetLabels()["example.com/ignored"] != "true" })
+			})
+
+			base := map[skipReason]float64{}
+			for _, reason := range reasons {
+				base[reason] = testutil.ToFloat64(reconcileSkipped.WithLabelValues(r.name, string(reason)))
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			for _, reason := range reasons {
+				want := 0.0
+				if reason == tc.reason {
+					want = 2
+				}
+				if got := testutil.ToFloat64(reconcileSkipped.WithLabelValues(r.name, string(reason))) - base[reason]; got != want {
+					t.Errorf("skipped reconciles with reason %s = %v, want %v", reason, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// OwnerUIDIndexField is the field index registered on the reconciled api type by Reconciler.WatchesOwner.
+const OwnerUIDIndexField = ".metadata.ownerReferences.uid"
+
+// WatchesOwner watches ownerType and enqueues every object of the reconciled api type that lists a changed owner in
+// its owner references. Use it for nested resources whose reconciliation depends on their parent.
+func (r *Reconciler) WatchesOwner(ownerType client.Object) *Reconciler {
+	if !r.ownerIndex {
+		r.ownerIndex = true
+		r.indexes = append(r.indexes, &reconcilerIndex{field: OwnerUIDIndexField, extractor: ownerUIDs})
+	}
+	r.controllerBuilder.Watches(ownerType, handler.EnqueueRequestsFromMapFunc(r.mapOwnerToOwned))
+	r.watched = append(r.watched, ownerType)
+	return r
+}
+
+func (r *Reconciler) mapOwnerToOwned(ctx context.Context, owner client.Object) []reconcile.Request {
+	listGVK := r.resourceGVK.GroupVersion().WithKind(r.resourceGVK.Kind + "List")
+	obj, err := r.mgr.GetScheme().New(listGVK)
+	if err != nil {
+		r.log.Error(err, "Cannot create list for owned objects", "gvk", listGVK)
+		return nil
+	}
+	list := obj.(client.ObjectList)
+
+	opts := []client.ListOption{
+		client.InNamespace(owner.GetNamespace()),
+		client.MatchingFields{OwnerUIDIndexField: string(owner.GetUID())},
+	}
+	if err = r.client.List(ctx, list, opts...); err != nil {
+		r.log.Error(err, "Failed to list owned objects", "owner", client.ObjectKeyFromObject(owner))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	_ = meta.EachListItem(list, func(item runtime.Object) error {
+		if o, ok := item.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+		}
+		return nil
+	})
+
+	return requests
+}
+
+// EnsureOwnerReference adds owner to the owner references of obj, or replaces the reference with the same UID in place.
+// Duplicate references to the owner are collapsed into one, and obj is left untouched when it already matches.
+func EnsureOwnerReference(obj client.Object, owner metav1.OwnerReference) {
+	refs := obj.GetOwnerReferences()
+	updated := make([]metav1.OwnerReference, 0, len(refs)+1)
+	found := false
+	for _, ref := range refs {
+		if ref.UID != owner.UID {
+			updated = append(updated, ref)
+			continue
+		}
+		if !found {
+			updated = append(updated, owner)
+			found = true
+		}
+	}
+	if !found {
+		updated = append(updated, owner)
+	}
+
+	if !equality.Semantic.DeepEqual(refs, updated) {
+		obj.SetOwnerReferences(updated)
+	}
+}
+
+func ownerUIDs(obj client.Object) []string {
+	refs := obj.GetOwnerReferences()
+	uids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		uids = append(uids, string(ref.UID))
+	}
+
+	return uids
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestOwnerUIDs(t *testing.T) {
+	obj := newTestObject("owned")
+	if got := ownerUIDs(obj); len(got) != 0 {
+		t.Errorf("ownerUIDs() = %v, want none", got)
+	}
+
+	obj.OwnerReferences = []metav1.OwnerReference{{UID: "first"}, {UID: "second"}}
+	if got, want := ownerUIDs(obj), []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ownerUIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestWatchesOwner(t *testing.T) {
+	owner := &healthObject{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "parent-uid"}}
+	ownedBy := func(name, namespace string, uid types.UID) *testObject {
+		obj := newTestObject(name)
+		obj.Namespace = namespace
+		obj.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: testGroupVersion.String(), Kind: "healthObject", Name: "parent", UID: uid,
+		}}
+		return obj
+	}
+
+	// building registers the index with the manager cache, which requires an api server, see TestWithIndex
+	tr := newTestReconciler(t)
+	r := NewReconciler(tr.mgr).For(&testObject{}).Named("owner")
+	r.WatchesOwner(&healthObject{}).WatchesOwner(&testObject{})
+	if len(r.indexes) != 1 || r.indexes[0].field != OwnerUIDIndexField || r.indexes[0].obj != nil {
+		t.Fatalf("indexes = %+v, want a single owner UID index on the reconciled api type", r.indexes)
+	}
+	r.resourceGVK = testGroupVersion.WithKind("testObject")
+
+	r.client = fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(
+			ownedBy("first", "default", "parent-uid"),
+			ownedBy("second", "default", "parent-uid"),
+			ownedBy("other-parent", "default", "other-uid"),
+			ownedBy("other-namespace", "other", "parent-uid"),
+			newTestObject("unowned"),
+		).
+		WithIndex(&testObject{}, OwnerUIDIndexField, ownerUIDs).
+		Build()
+
+	requests := r.mapOwnerToOwned(context.Background(), owner)
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Name < requests[j].Name })
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "first"}},
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "second"}},
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("mapOwnerToOwned() = %v, want %v", requests, want)
+	}
+}
+
+// ownerRefCounter counts calls to SetOwnerReferences.
+type ownerRefCounter struct {
+	*testObject
+	sets int
+}
+
+func (o *ownerRefCounter) SetOwnerReferences(refs []metav1.OwnerReference) {
+	o.sets++
+	o.testObject.SetOwnerReferences(refs)
+}
+
+func TestEnsureOwnerReference(t *testing.T) {
+	controller, block := true, true
+	owner := metav1.OwnerReference{
+		APIVersion: testGroupVersion.String(), Kind: "healthObject", Name: "parent", UID: "parent-uid",
+		Controller: &controller,
+	}
+	blocking := *owner.DeepCopy()
+	blocking.BlockOwnerDeletion = &block
+	other := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+
+	cases := []struct {
+		name     string
+		existing []metav1.OwnerReference
+		owner    metav1.OwnerReference
+		want     []metav1.OwnerReference
+		sets     int
+	}{
+		{name: "add", owner: owner, want: []metav1.OwnerReference{owner}, sets: 1},
+		{
+			name: "add next to others", existing: []metav1.OwnerReference{other}, owner: owner,
+			want: []metav1.OwnerReference{other, owner}, sets: 1,
+		},
+		{
+			name: "update in place", existing: []metav1.OwnerReference{owner, other}, owner: blocking,
+			want: []metav1.OwnerReference{blocking, other}, sets: 1,
+		},
+		{
+			name: "collapse duplicates", existing: []metav1.OwnerReference{owner, other, blocking}, owner: blocking,
+			want: []metav1.OwnerReference{blocking, other}, sets: 1,
+		},
+		{
+			name: "no-op", existing: []metav1.OwnerReference{other, owner}, owner: owner,
+			want: []metav1.OwnerReference{other, owner}, sets: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &ownerRefCounter{testObject: newTestObject("owned")}
+			obj.OwnerReferences = tc.existing
+
+			EnsureOwnerReference(obj, tc.owner)
+			if !reflect.DeepEqual(obj.OwnerReferences, tc.want) {
+				t.Errorf("owner references = %+v, want %+v", obj.OwnerReferences, tc.want)
+			}
+			if obj.sets != tc.sets {
+				t.Errorf("owner references set %d times, want %d", obj.sets, tc.sets)
+			}
+
+			// applying the same reference again never changes the object
+			obj.sets = 0
+			EnsureOwnerReference(obj, tc.owner)
+			if obj.sets != 0 || !reflect.DeepEqual(obj.OwnerReferences, tc.want) {
+				t.Errorf("repeated call changed owner references to %+v", obj.OwnerReferences)
+			}
+		})
+	}
+}
+
+// mapperManager serves a fixed RESTMapper in place of the manager's discovery-based one.
+type mapperManager struct {
+	ctrl.Manager
+	mapper meta.RESTMapper
+}
+
+func (m *mapperManager) GetRESTMapper() meta.RESTMapper {
+	return m.mapper
+}
+
+// failingMapper fails every lookup with an error other than a missing match.
+type failingMapper struct {
+	meta.RESTMapper
+}
+
+func (failingMapper) RESTMapping(schema.GroupKind, ...string) (*meta.RESTMapping, error) {
+	return nil, errors.New("discovery unavailable")
+}
+
+func TestOwnsIfAvailable(t *testing.T) {
+	healthGVK := testGroupVersion.WithKind("healthObject")
+	served := meta.NewDefaultRESTMapper([]schema.GroupVersion{testGroupVersion})
+	served.Add(healthGVK, meta.RESTScopeNamespace)
+
+	cases := []struct {
+		name    string
+		mapper  meta.RESTMapper
+		watched bool
+		wantErr bool
+	}{
+		{name: "served", mapper: served, watched: true},
+		{name: "not served", mapper: meta.NewDefaultRESTMapper(nil)},
+		{name: "discovery error", mapper: failingMapper{}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := newTestReconciler(t)
+			mgr := &mapperManager{Manager: tr.mgr, mapper: tc.mapper}
+
+			r := NewReconciler(mgr).For(&testObject{}).Named("optional").OwnsIfAvailable(&healthObject{})
+			r.client = tr.client
+			_, err := r.Build()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Build() error = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			watched := false
+			for _, gvk := range r.WatchedKinds() {
+				watched = watched || gvk == healthGVK
+			}
+			if watched != tc.watched {
+				t.Errorf("WatchedKinds() = %v, want %s watched %t", r.WatchedKinds(), healthGVK, tc.watched)
+			}
+		})
+	}
+}
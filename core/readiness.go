@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReadinessPollInterval is the requeue interval returned by ReadinessResult for objects that are not ready yet.
+var ReadinessPollInterval = 10 * time.Second
+
+// ReadinessResult returns the reconcile result a component should return after a readiness check.
+func ReadinessResult(ready bool) ctrl.Result {
+	if ready {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: ReadinessPollInterval}
+}
+
+// DeploymentReady reports whether the named Deployment owned by the reconcile object has rolled out all of its
+// desired replicas.
+func (c *Context) DeploymentReady(name string) (bool, error) {
+	deploy := &appsv1.Deployment{}
+	if err := c.getOwned(name, deploy); err != nil {
+		return false, err
+	}
+
+	desired := replicasOrDefault(deploy.Spec.Replicas)
+	status := deploy.Status
+
+	return status.ObservedGeneration >= deploy.Generation &&
+		status.UpdatedReplicas == desired &&
+		status.AvailableReplicas == desired &&
+		status.Replicas == desired, nil
+}
+
+// StatefulSetReady reports whether the named StatefulSet owned by the reconcile object has all of its desired
+// replicas ready at the current revision.
+func (c *Context) StatefulSetReady(name string) (bool, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := c.getOwned(name, sts); err != nil {
+		return false, err
+	}
+
+	desired := replicasOrDefault(sts.Spec.Replicas)
+	status := sts.Status
+
+	return status.ObservedGeneration >= sts.Generation &&
+		status.ReadyReplicas == desired &&
+		status.UpdatedReplicas == desired &&
+		status.CurrentRevision == status.UpdateRevision, nil
+}
+
+// JobComplete reports whether the named Job owned by the reconcile object has completed. A failed Job returns an
+// error.
+func (c *Context) JobComplete(name string) (bool, error) {
+	job := &batchv1.Job{}
+	if err := c.getOwned(name, job); err != nil {
+		return false, err
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("job %s failed: %s", name, cond.Message)
+		}
+	}
+
+	return false, nil
+}
+
+func (c *Context) getOwned(name string, obj client.Object) error {
+	key := client.ObjectKey{Namespace: c.Object.GetNamespace(), Name: name}
+	if err := c.Client.Get(c, key, obj); err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, c.Object) {
+		return fmt.Errorf("%T %s is not controlled by %s", obj, key, client.ObjectKeyFromObject(c.Object))
+	}
+
+	return nil
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newReadinessContext returns a context for a reconcile object controlling objs.
+func newReadinessContext(objs ...client.Object) *Context {
+	owner := newTestObject("owner")
+	owner.UID = "owner-uid"
+	isController := true
+	for _, obj := range objs {
+		obj.SetNamespace(owner.Namespace)
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: testGroupVersion.String(), Kind: "testObject", Name: owner.Name, UID: owner.UID,
+			Controller: &isController,
+		}})
+	}
+
+	return &Context{
+		Context: context.Background(),
+		Object:  owner,
+		Client:  fake.NewClientBuilder().WithObjects(objs...).Build(),
+	}
+}
+
+func TestDeploymentReady(t *testing.T) {
+	replicas := int32(2)
+	deployment := func(name string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 3},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 3,
+				Replicas:           2,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+	ctx := newReadinessContext(deployment("ready", 2), deployment("rolling", 1))
+
+	ready, err := ctx.DeploymentReady("ready")
+	if err != nil || !ready {
+		t.Errorf("DeploymentReady(ready) = %t, %v, want ready", ready, err)
+	}
+	if res := ReadinessResult(ready); !res.IsZero() {
+		t.Errorf("ReadinessResult() = %+v for a ready deployment, want no requeue", res)
+	}
+
+	ready, err = ctx.DeploymentReady("rolling")
+	if err != nil || ready {
+		t.Errorf("DeploymentReady(rolling) = %t, %v, want not ready", ready, err)
+	}
+	if res := ReadinessResult(ready); res.RequeueAfter != ReadinessPollInterval {
+		t.Errorf("ReadinessResult() = %+v for a rolling deployment, want a requeue after %v", res, ReadinessPollInterval)
+	}
+
+	if _, err = ctx.DeploymentReady("missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("DeploymentReady(missing) error = %v, want not found", err)
+	}
+}
+
+func TestReadinessRequiresControlledObject(t *testing.T) {
+	ctx := newReadinessContext()
+	foreign := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default"}}
+	if err := ctx.Client.Create(ctx, foreign); err != nil {
+		t.Fatalf("cannot create deployment: %v", err)
+	}
+
+	if _, err := ctx.DeploymentReady("foreign"); err == nil {
+		t.Error("DeploymentReady() succeeded for a deployment not controlled by the reconcile object")
+	}
+}
+
+func TestStatefulSetReady(t *testing.T) {
+	statefulSet := func(name, updateRevision string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: appsv1.StatefulSetStatus{
+				ReadyReplicas:   1,
+				UpdatedReplicas: 1,
+				CurrentRevision: "rev-1",
+				UpdateRevision:  updateRevision,
+			},
+		}
+	}
+	ctx := newReadinessContext(statefulSet("ready", "rev-1"), statefulSet("updating", "rev-2"))
+
+	if ready, err := ctx.StatefulSetReady("ready"); err != nil || !ready {
+		t.Errorf("StatefulSetReady(ready) = %t, %v, want ready", ready, err)
+	}
+	if ready, err := ctx.StatefulSetReady("updating"); err != nil || ready {
+		t.Errorf("StatefulSetReady(updating) = %t, %v, want not ready", ready, err)
+	}
+}
+
+func TestJobComplete(t *testing.T) {
+	job := func(name string, condType batchv1.JobConditionType) *batchv1.Job {
+		j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if condType != "" {
+			j.Status.Conditions = []batchv1.JobCondition{{Type: condType, Status: corev1.ConditionTrue, Message: "backoff"}}
+		}
+		return j
+	}
+	ctx := newReadinessContext(job("complete", batchv1.JobComplete), job("running", ""), job("failed", batchv1.JobFailed))
+
+	if done, err := ctx.JobComplete("complete"); err != nil || !done {
+		t.Errorf("JobComplete(complete) = %t, %v, want complete", done, err)
+	}
+	if done, err := ctx.JobComplete("running"); err != nil || done {
+		t.Errorf("JobComplete(running) = %t, %v, want incomplete", done, err)
+	}
+	if _, err := ctx.JobComplete("failed"); err == nil {
+		t.Error("JobComplete(failed) succeeded for a failed job")
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type readKey struct {
+	typ reflect.Type
+	key client.ObjectKey
+}
+
+// Get reads an object like Client.Get but memoizes the result for the remainder of the reconcile, so that components
+// reading the same object share a single request. Writes made through Context and action helpers invalidate the
+// memoized copy, see Forget.
+func (c *Context) Get(key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	rk := readKey{typ: reflect.TypeOf(obj), key: key}
+	if cached, ok := c.reads[rk]; ok {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(cached.DeepCopyObject()).Elem())
+		return nil
+	}
+
+	if err := c.Client.Get(c, key, obj, opts...); err != nil {
+		return err
+	}
+
+	if c.reads == nil {
+		c.reads = map[readKey]client.Object{}
+	}
+	c.reads[rk] = obj.DeepCopyObject().(client.Object)
+
+	return nil
+}
+
+// GetOptional reads an object that may not exist. It returns false without an error when the object is not found.
+func (c *Context) GetOptional(key types.NamespacedName, obj client.Object) (bool, error) {
+	if err := c.Client.Get(c, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Forget drops the memoized copy of obj read with Get. Helpers writing objects call it, callers writing objects
+// through Client directly should call it as well so that later reads in the same reconcile observe the write.
+func (c *Context) Forget(obj client.Object) {
+	delete(c.reads, readKey{typ: reflect.TypeOf(obj), key: client.ObjectKeyFromObject(obj)})
+}
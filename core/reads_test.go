@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestContextGet(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	gets := 0
+	cl := fake.NewClientBuilder().
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	ctx := &Context{Context: context.Background(), Client: cl}
+	key := client.ObjectKeyFromObject(cm)
+
+	first := &corev1.ConfigMap{}
+	if err := ctx.Get(key, first); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	first.Data["key"] = "mutated"
+
+	second := &corev1.ConfigMap{}
+	if err := ctx.Get(key, second); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if gets != 1 {
+		t.Errorf("client reads = %d, want the second Get served from the cache", gets)
+	}
+	if second.Data["key"] != "value" {
+		t.Errorf("cached data = %v, want it unaffected by changes to earlier results", second.Data)
+	}
+
+	ctx.Forget(second)
+	if err := ctx.Get(key, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if gets != 2 {
+		t.Errorf("client reads = %d, want a read after the object was forgotten", gets)
+	}
+
+	missing := client.ObjectKey{Namespace: "default", Name: "missing"}
+	for i := 0; i < 2; i++ {
+		if err := ctx.Get(missing, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+			t.Fatalf("Get() error = %v, want not found", err)
+		}
+	}
+	if gets != 4 {
+		t.Errorf("client reads = %d, want failed reads not to be cached", gets)
+	}
+}
+
+func TestContextGetOptional(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+	errBroken := errors.New("broken")
+	cl := fake.NewClientBuilder().
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if key.Name == "broken" {
+					return errBroken
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	ctx := &Context{Context: context.Background(), Client: cl}
+
+	found := &corev1.ConfigMap{}
+	ok, err := ctx.GetOptional(client.ObjectKeyFromObject(cm), found)
+	if !ok || err != nil {
+		t.Fatalf("GetOptional() = %t, %v, want the object found", ok, err)
+	}
+	if found.Data["key"] != "value" {
+		t.Errorf("data = %v, want the stored object", found.Data)
+	}
+
+	fallback := &corev1.ConfigMap{Data: map[string]string{"key": "default"}}
+	ok, err = ctx.GetOptional(client.ObjectKey{Namespace: "default", Name: "missing"}, fallback)
+	if ok || err != nil {
+		t.Fatalf("GetOptional() = %t, %v, want not found without an error", ok, err)
+	}
+	if fallback.Data["key"] != "default" {
+		t.Errorf("data = %v, want the default kept for a missing object", fallback.Data)
+	}
+
+	ok, err = ctx.GetOptional(client.ObjectKey{Namespace: "default", Name: "broken"}, &corev1.ConfigMap{})
+	if ok || !errors.Is(err, errBroken) {
+		t.Errorf("GetOptional() = %t, %v, want the read error returned", ok, err)
+	}
+}
@@ -2,12 +2,24 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -17,23 +29,136 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/dominodatalab/controller-util/metadata"
 )
 
 var getGvk = apiutil.GVKForObject
 
+// DefaultComponentNamePolicy requires component names to be non-empty lowercase alphanumerics separated by dashes, since
+// they become part of finalizer names. Names with uppercase letters, dots or underscores are rejected at Build, use
+// WithComponentNamePolicy to accept them.
+var DefaultComponentNamePolicy = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// MaxConditionRequeueInterval caps the backoff of the intervals configured using Reconciler.WithConditionRequeue. It
+// matches the maximum delay of the default controller rate limiter.
+var MaxConditionRequeueInterval = 1000 * time.Second
+
 const SkipReconcileAnnotation = "controller-util.dominodatalab.com/skip-reconcile"
 
+// PreFetchedObjectContextDataKey is the ContextData key used by composed controllers to hand an already fetched object
+// to a reconciler built with WithPreFetchedObject. The object is only used when it matches the request and carries a
+// resourceVersion. It is not compared against the cache, so the caller must store an object as fresh as the one it
+// would read itself and without unpersisted changes, e.g. a copy of the object taken right after fetching or patching it.
+const PreFetchedObjectContextDataKey = "controller-util.dominodatalab.com/pre-fetched-object"
+
+const (
+	// ValidConditionType is set on objects that support conditions when an object validator is configured.
+	ValidConditionType = "Valid"
+
+	// DeletionConditionType is set on objects that support conditions while a deletion gate blocks finalization.
+	DeletionConditionType = "Deletion"
+	// DeletionBlockedReason indicates the deletion gate refused to let finalizers run.
+	DeletionBlockedReason = "Blocked"
+
+	// FinalizingConditionType is set on objects that support conditions while components report finalization progress.
+	FinalizingConditionType = "Finalizing"
+	// FinalizingInProgressReason indicates at least one component has not finished finalizing.
+	FinalizingInProgressReason = "InProgress"
+
+	// SuspendedConditionType is set on objects that support conditions while the suspend field holds back component
+	// reconciliation.
+	SuspendedConditionType = "Suspended"
+	// SuspendedReason indicates the suspend field of the object is set.
+	SuspendedReason = "Suspended"
+
+	// StalledConditionType is set on objects that support conditions once reconciliation failed repeatedly.
+	StalledConditionType = "Stalled"
+
+	// ReconciliationConditionType is set on objects that support conditions while reconciliation is paused.
+	ReconciliationConditionType = "Reconciliation"
+	// ReconciliationPausedReason indicates reconciliation was skipped due to annotation, paused field, namespace
+	// annotation or skip predicate.
+	ReconciliationPausedReason = "Paused"
+)
+
+type SkipPredicate func(client.Object) bool
+
+// SpecRequeueIntervalFunc extracts a requeue interval from the object, e.g. a spec.pollInterval field. It returns
+// false when the object does not specify one.
+type SpecRequeueIntervalFunc func(obj client.Object) (time.Duration, bool)
+
+// DeletionGate decides whether finalizers may run for an object pending deletion. When deletion is not allowed, the
+// returned reason is surfaced via condition and event.
+type DeletionGate func(ctx *Context) (allow bool, reason string, err error)
+
+// ObjectValidator checks the fetched object before components run. Components are skipped when it returns an error.
+type ObjectValidator func(client.Object) error
+
+// MetaCloner returns a copy of an object suitable for building the metadata patch snapshots. The copy must not share
+// metadata with the original.
+type MetaCloner func(client.Object) client.Object
+
+// DiffReporter receives the patch computed for the "metadata", "finalizers" and "status" writes of a reconcile before
+// they are applied. It is only called when the patch is not empty.
+type DiffReporter func(kind string, diff []byte)
+
+// ContextDataProvider computes a ContextData value for the object at the start of each reconcile.
+type ContextDataProvider func(ctx context.Context, obj client.Object) (interface{}, error)
+
+type contextDataProvider struct {
+	key string
+	fn  ContextDataProvider
+}
+
+type reconcilerIndex struct {
+	obj       client.Object
+	field     string
+	extractor client.IndexerFunc
+}
+
 type reconcilerComponent struct {
 	name string
 	comp Component
 
 	finalizer     FinalizerComponent
 	finalizerName string
+
+	multiFinalizer  MultiFinalizerComponent
+	multiFinalizers []namedFinalizer
+
+	recorder record.EventRecorder
+}
+
+type optionalOwns struct {
+	obj  client.Object
+	opts []builder.OwnsOption
+}
+
+type namedFinalizer struct {
+	name      string
+	finalizer string
+}
+
+func (rc *reconcilerComponent) hasPendingFinalizer(obj client.Object) bool {
+	if rc.finalizer != nil && controllerutil.ContainsFinalizer(obj, rc.finalizerName) {
+		return true
+	}
+	for _, nf := range rc.multiFinalizers {
+		if controllerutil.ContainsFinalizer(obj, nf.finalizer) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Reconciler struct {
 	name              string
 	resourceName      string
+	resourceGVK       schema.GroupVersionKind
+	logGVK            bool
 	mgr               ctrl.Manager
 	controllerBuilder *ctrl.Builder
 	apiType           client.Object
@@ -43,11 +168,72 @@ type Reconciler struct {
 	abortNotFound     bool
 	webhooksEnabled   bool
 	finalizerBaseName string
+	skipPredicate     SkipPredicate
+	metadata          *metadata.Provider
+	clientset         *clientsetCache
+	triggers          *triggerStore
+	diffReporter      DiffReporter
+	dryRun            bool
+	statusDisabled    bool
+	preFetched        bool
+	conditionRequeue  map[string]time.Duration
+	conditionBackoff  sync.Map
+	terminating       sync.Map
+	completionEvents  bool
+	metaCloner        MetaCloner
+	validator         ObjectValidator
+	abortOnSpecChange bool
+	deletionGate      DeletionGate
+	addToScheme       []func(*runtime.Scheme) error
+	conditions        ConditionsAccessor
+	conditionMetrics  bool
+	conditionStates   sync.Map
+	rvSkip            bool
+	reconciledRVs     sync.Map
+	generationSkip    bool
+	reconciledGens    sync.Map
+	requeuedOnce      sync.Map
+	errorAggregator   func([]error) error
+	ownerIndex        bool
+	compRecorders     bool
+	drainTimeout      time.Duration
+	optionalOwns      []*optionalOwns
+	errorClassifier   ErrorClassifier
+	watched           []client.Object
+	watchedGVKs       []schema.GroupVersionKind
+	mutationGuard     bool
+	postRequeue       time.Duration
+	namePolicy        *regexp.Regexp
+	reconcileStatus   bool
+	logConstructor    func(*reconcile.Request) logr.Logger
+	finalizeTransform func(client.Object)
+	specRequeue       SpecRequeueIntervalFunc
+	conditionPrefix   bool
+	pausedField       func(client.Object) bool
+	pausedObjs        sync.Map
+	eventSource       string
+	allowedConditions map[string]struct{}
+	strictConditions  bool
+	dataProviders     []*contextDataProvider
+	namespacePauseKey string
+	defaulting        bool
+	stalledAfter      int
+	failures          sync.Map
+	warmupGVKs        []schema.GroupVersionKind
+
+	quietLogs           bool
+	excludedAnnotations []string
+	excludedLabels      []string
+	softDeleteLabel     string
+	componentConditions ComponentConditionsAccessor
+	reconcileFilter     func(obj client.Object) bool
+	suspendField        func(obj client.Object) bool
 
 	patcher     *Patch
 	recorder    record.EventRecorder
 	controller  controller.Controller
 	components  []*reconcilerComponent
+	indexes     []*reconcilerIndex
 	contextData ContextData
 }
 
@@ -59,6 +245,9 @@ func NewReconciler(mgr ctrl.Manager) *Reconciler {
 		components:        []*reconcilerComponent{},
 		controllerBuilder: builder.ControllerManagedBy(mgr),
 		contextData:       ContextData{},
+		clientset:         &clientsetCache{},
+		triggers:          newTriggerStore(),
+		conditions:        ConditionObjectAccessor,
 		abortNotFound:     true,
 	}
 }
@@ -66,6 +255,7 @@ func NewReconciler(mgr ctrl.Manager) *Reconciler {
 func (r *Reconciler) For(apiType client.Object, opts ...builder.ForOption) *Reconciler {
 	r.apiType = apiType
 	r.controllerBuilder = r.controllerBuilder.For(apiType, opts...)
+	r.watched = append(r.watched, apiType)
 
 	return r
 }
@@ -75,15 +265,37 @@ func (r *Reconciler) Component(name string, comp Component, opts ...builder.Owns
 
 	if ownedComp, ok := comp.(OwnedComponent); ok {
 		r.controllerBuilder.Owns(ownedComp.Kind(), opts...)
+		r.watched = append(r.watched, ownedComp.Kind())
 	}
 	if finalizer, ok := comp.(FinalizerComponent); ok {
 		rc.finalizer = finalizer
 	}
+	if progress, ok := comp.(FinalizerProgressComponent); ok {
+		rc.finalizer = progressFinalizer{progress}
+	}
+	if multiFinalizer, ok := comp.(MultiFinalizerComponent); ok {
+		rc.multiFinalizer = multiFinalizer
+	}
 	r.components = append(r.components, rc)
 
 	return r
 }
 
+// OwnsIfAvailable owns obj like Component does for OwnedComponent kinds, but only when the cluster serves its api
+// type when the controller is built. Use it for optional CRDs.
+func (r *Reconciler) OwnsIfAvailable(obj client.Object, opts ...builder.OwnsOption) *Reconciler {
+	r.optionalOwns = append(r.optionalOwns, &optionalOwns{obj: obj, opts: opts})
+	return r
+}
+
+// WatchesOwned watches objects controlled by the reconciled api type and records each triggering event so that
+// components can inspect them via Context.Triggers.
+func (r *Reconciler) WatchesOwned(obj client.Object, opts ...builder.WatchesOption) *Reconciler {
+	r.controllerBuilder.Watches(obj, &triggerHandler{r: r}, opts...)
+	r.watched = append(r.watched, obj)
+	return r
+}
+
 func (r *Reconciler) Named(name string) *Reconciler {
 	r.name = name
 	r.controllerBuilder.Named(name)
@@ -95,32 +307,405 @@ func (r *Reconciler) ReconcileNotFound() *Reconciler {
 	return r
 }
 
+// WithContextData makes obj available to components under key. Each reconcile works on a copy of the context data, so
+// values written to Context.Data by components do not carry over to other reconciles.
 func (r *Reconciler) WithContextData(key string, obj interface{}) *Reconciler {
 	r.contextData[key] = obj
 	return r
 }
 
+func (r *Reconciler) WithContextDataProvider(key string, fn ContextDataProvider) *Reconciler {
+	r.dataProviders = append(r.dataProviders, &contextDataProvider{key: key, fn: fn})
+	return r
+}
+
 func (r *Reconciler) WithControllerOptions(opts controller.Options) *Reconciler {
 	// this library dynamically builds a reconciler, hence, we do not allow an override here
 	opts.Reconciler = nil
+	if opts.LogConstructor == nil {
+		opts.LogConstructor = r.logConstructor
+	}
 
 	r.controllerBuilder.WithOptions(opts)
 	return r
 }
 
+func (r *Reconciler) WithIndex(obj client.Object, field string, extractor client.IndexerFunc) *Reconciler {
+	r.indexes = append(r.indexes, &reconcilerIndex{obj: obj, field: field, extractor: extractor})
+	return r
+}
+
+func (r *Reconciler) WithMetadataProvider(p *metadata.Provider) *Reconciler {
+	r.metadata = p
+	return r
+}
+
+func (r *Reconciler) WithDiffReporter(fn DiffReporter) *Reconciler {
+	r.diffReporter = fn
+	return r
+}
+
+// WithDryRun runs components as usual but drops the metadata, finalizer and status changes of the reconcile object
+// instead of patching them. Combine it with WithDiffReporter to preview those changes. Writes made by components
+// themselves are not affected.
+func (r *Reconciler) WithDryRun() *Reconciler {
+	r.dryRun = true
+	return r
+}
+
+func (r *Reconciler) WithObjectGVKLogging() *Reconciler {
+	r.logGVK = true
+	return r
+}
+
+// WithAllowedConditionTypes restricts the condition types components may set. Setting any other type logs a message,
+// or fails the component when WithStrictConditionTypes is also used. Condition types managed by this library are
+// always allowed.
+func (r *Reconciler) WithAllowedConditionTypes(types ...string) *Reconciler {
+	if r.allowedConditions == nil {
+		r.allowedConditions = map[string]struct{}{
+			ValidConditionType:          {},
+			StalledConditionType:        {},
+			DeletionConditionType:       {},
+			FinalizingConditionType:     {},
+			SuspendedConditionType:      {},
+			TerminalErrorConditionType:  {},
+			SingletonConditionType:      {},
+			ReconciliationConditionType: {},
+		}
+	}
+	for _, t := range types {
+		r.allowedConditions[t] = struct{}{}
+	}
+	return r
+}
+
+func (r *Reconciler) WithStrictConditionTypes() *Reconciler {
+	r.strictConditions = true
+	return r
+}
+
+// WithEventSource overrides the source component of recorded events, which defaults to "<name>-controller".
+func (r *Reconciler) WithEventSource(name string) *Reconciler {
+	r.eventSource = name
+	return r
+}
+
+// WithLastReconcileStatus records the result and error of each reconcile in the status of objects implementing
+// ReconcileStatusObject.
+//
+// NOTE: every write bumps the resourceVersion and triggers another reconcile. The status is therefore only written when
+// the result or error differs from the recorded one, and LastReconcileTime is the time of the last such change rather
+// than of the last reconcile. Results that change on every reconcile, e.g. error messages containing timestamps, cause
+// a reconcile loop unless the api type is watched using a predicate ignoring status updates, such as
+// predicate.GenerationChangedPredicate.
+func (r *Reconciler) WithLastReconcileStatus() *Reconciler {
+	r.reconcileStatus = true
+	return r
+}
+
+// WithComponentNamePolicy replaces DefaultComponentNamePolicy, which component names are validated against at Build.
+func (r *Reconciler) WithComponentNamePolicy(policy *regexp.Regexp) *Reconciler {
+	r.namePolicy = policy
+	return r
+}
+
+// WithPostReconcileRequeue requeues objects after every successful reconcile, unless components requested a shorter
+// interval, to re-observe external state that is not covered by watches. Failed reconciles are retried as usual.
+func (r *Reconciler) WithPostReconcileRequeue(interval time.Duration) *Reconciler {
+	r.postRequeue = interval
+	return r
+}
+
+// WithObjectMutationGuard logs a warning whenever components change the status of an object whose status is not
+// patched, or when the metadata patch unexpectedly carries status fields. Meant for development.
+func (r *Reconciler) WithObjectMutationGuard() *Reconciler {
+	r.mutationGuard = true
+	return r
+}
+
+// WithErrorClassifier classifies the errors of failed reconciles, e.g. using DefaultErrorClassifier. Without a
+// classifier all errors are retried with the controller's backoff.
+func (r *Reconciler) WithErrorClassifier(fn ErrorClassifier) *Reconciler {
+	r.errorClassifier = fn
+	return r
+}
+
+// WithErrorAggregator replaces utilerrors.NewAggregate as the func combining the errors of a reconcile. It is only
+// called when at least one error occurred.
+func (r *Reconciler) WithErrorAggregator(fn func([]error) error) *Reconciler {
+	r.errorAggregator = fn
+	return r
+}
+
+// WithComponentRecorders gives each component its own event recorder, using "<controller>-<component>" as the event
+// source, which is exposed as Context.Recorder while the component runs.
+func (r *Reconciler) WithComponentRecorders() *Reconciler {
+	r.compRecorders = true
+	return r
+}
+
+// WithShutdownDrainTimeout persists the metadata and status changes of a reconcile interrupted by manager shutdown
+// using a separate context bounded by timeout. Without it, patches of an interrupted reconcile fail with the
+// cancelled context.
+func (r *Reconciler) WithShutdownDrainTimeout(timeout time.Duration) *Reconciler {
+	r.drainTimeout = timeout
+	return r
+}
+
+// WithQuietReconcileLogs logs the messages marking the start and end of each reconcile at V(1).
+func (r *Reconciler) WithQuietReconcileLogs() *Reconciler {
+	r.quietLogs = true
+	return r
+}
+
+// WithSoftDeleteLabel finalizes objects labeled with key=true as if they had a deletion timestamp.
+func (r *Reconciler) WithSoftDeleteLabel(key string) *Reconciler {
+	r.softDeleteLabel = key
+	return r
+}
+
+// WithMetadataPatchExclusions keeps changes to the given annotation and label keys out of the metadata patch, leaving
+// them to whichever controller manages them.
+func (r *Reconciler) WithMetadataPatchExclusions(annotationKeys, labelKeys []string) *Reconciler {
+	r.excludedAnnotations = append(r.excludedAnnotations, annotationKeys...)
+	r.excludedLabels = append(r.excludedLabels, labelKeys...)
+	return r
+}
+
+// WithCacheWarmup starts informers for the given kinds when the manager starts and adds a readiness check that fails
+// until they have synced.
+func (r *Reconciler) WithCacheWarmup(gvks ...schema.GroupVersionKind) *Reconciler {
+	r.warmupGVKs = append(r.warmupGVKs, gvks...)
+	return r
+}
+
+// WithStalledAfter sets the Stalled condition and records a warning event once n consecutive reconciles of an object
+// have failed. The condition is removed after the next reconcile that runs its components without errors; skipped,
+// invalid, suspended or interrupted reconciles and those failing only with transient errors leave it in place.
+func (r *Reconciler) WithStalledAfter(n int) *Reconciler {
+	r.stalledAfter = n
+	return r
+}
+
+// WithObjectDefaulting applies the scheme's defaulting funcs to the fetched object and persists any resulting changes
+// before components run.
+func (r *Reconciler) WithObjectDefaulting() *Reconciler {
+	r.defaulting = true
+	return r
+}
+
+// WithNamespacePauseAnnotation skips reconciliation of objects whose namespace carries the annotation key with a value
+// of "true". Namespaces are read through the manager's client, hence from the cache.
+func (r *Reconciler) WithNamespacePauseAnnotation(key string) *Reconciler {
+	r.namespacePauseKey = key
+	return r
+}
+
+// WithPausedField skips reconciliation while fn reports the object as paused, e.g. based on a spec.paused field. Paused
+// objects are handled like objects carrying the skip annotation.
+func (r *Reconciler) WithPausedField(fn func(obj client.Object) bool) *Reconciler {
+	r.pausedField = fn
+	return r
+}
+
+// WithSuspendField holds back component reconciliation while fn reports the object as suspended, e.g. based on a
+// spec.suspend field. In contrast to WithPausedField, finalizers are still registered and run. Suspended objects that
+// support conditions carry the Suspended condition.
+func (r *Reconciler) WithSuspendField(fn func(obj client.Object) bool) *Reconciler {
+	r.suspendField = fn
+	return r
+}
+
+// WithReconcileFilter ends reconciles early for fetched objects that fn rejects. Unlike skipped objects, filtered
+// objects are left untouched.
+func (r *Reconciler) WithReconcileFilter(fn func(obj client.Object) bool) *Reconciler {
+	r.reconcileFilter = fn
+	return r
+}
+
+func (r *Reconciler) WithSkipPredicate(fn SkipPredicate) *Reconciler {
+	r.skipPredicate = fn
+	return r
+}
+
+// WithoutStatus disables status patching for api types that do not serve a status subresource. Types without a Status
+// field are detected automatically at Build.
+func (r *Reconciler) WithoutStatus() *Reconciler {
+	r.statusDisabled = true
+	return r
+}
+
+// WithConditionRequeue adjusts the reconcile result based on the reasons of non-True conditions on the object after all
+// components have run. A positive interval requeues no later than that interval, taking the shorter of it and any
+// RequeueAfter returned by components. The interval doubles with every consecutive reconcile that leaves the condition
+// non-True, up to MaxConditionRequeueInterval, and is reset once the condition turns True or is removed. A zero or
+// negative interval marks the reason as terminal and suppresses all requeues, including those requested by components;
+// errors are still returned and retried by controller-runtime.
+func (r *Reconciler) WithConditionRequeue(intervals map[string]time.Duration) *Reconciler {
+	r.conditionRequeue = intervals
+	return r
+}
+
+// WithAbortOnSpecChange periodically re-fetches the object while components run and cancels the context passed to
+// them when a newer generation is observed. Remaining components are skipped and the object is requeued.
+func (r *Reconciler) WithAbortOnSpecChange() *Reconciler {
+	r.abortOnSpecChange = true
+	return r
+}
+
+// WithSchemeBuilder registers types with the manager's scheme at the start of Build, e.g. using the AddToScheme
+// func of a scheme.Builder.
+func (r *Reconciler) WithSchemeBuilder(addToScheme ...func(*runtime.Scheme) error) *Reconciler {
+	r.addToScheme = append(r.addToScheme, addToScheme...)
+	return r
+}
+
+// WithResourceVersionSkip bypasses components implementing SkipUnchangedComponent when the object's resourceVersion
+// matches the one recorded after the last reconcile. A resourceVersion is only recorded when that reconcile finished
+// without errors and without requesting a requeue, so time-based requeues always run every component.
+func (r *Reconciler) WithResourceVersionSkip() *Reconciler {
+	r.rvSkip = true
+	return r
+}
+
+// WithObjectGenerationSkip bypasses components implementing StatusOnlyComponent when the object's generation matches
+// the one recorded after the last reconcile that finished without errors or requeues, i.e. for metadata-only changes.
+func (r *Reconciler) WithObjectGenerationSkip() *Reconciler {
+	r.generationSkip = true
+	return r
+}
+
+// WithComponentConditionPrefix prefixes the types of conditions set by a component with "<component name>.".
+func (r *Reconciler) WithComponentConditionPrefix() *Reconciler {
+	r.conditionPrefix = true
+	return r
+}
+
+// WithSpecRequeueInterval requeues objects after the interval read from the object whenever components do not
+// request an earlier requeue.
+func (r *Reconciler) WithSpecRequeueInterval(fn SpecRequeueIntervalFunc) *Reconciler {
+	r.specRequeue = fn
+	return r
+}
+
+// WithObjectTransformFinalize registers a func that may modify the object sent with the finalizer patch, which is
+// issued whenever components add or remove finalizers.
+func (r *Reconciler) WithObjectTransformFinalize(fn func(client.Object)) *Reconciler {
+	r.finalizeTransform = fn
+	return r
+}
+
+// WithLogConstructor sets the controller's log constructor and uses the logger it returns as the base logger for
+// each reconcile.
+func (r *Reconciler) WithLogConstructor(fn func(*reconcile.Request) logr.Logger) *Reconciler {
+	r.logConstructor = fn
+	r.controllerBuilder.WithLogConstructor(fn)
+	return r
+}
+
+func (r *Reconciler) WithConditionMetrics() *Reconciler {
+	r.conditionMetrics = true
+	return r
+}
+
+// WithComponentConditionsAccessor routes the conditions set by each component into the sub-object returned by fn.
+func (r *Reconciler) WithComponentConditionsAccessor(fn ComponentConditionsAccessor) *Reconciler {
+	r.componentConditions = fn
+	return r
+}
+
+// WithConditionsAccessor overrides how conditions are located on the reconcile object, for types that do not implement
+// ConditionObject.
+func (r *Reconciler) WithConditionsAccessor(fn ConditionsAccessor) *Reconciler {
+	r.conditions = fn
+	return r
+}
+
+func (r *Reconciler) WithDeletionGate(fn DeletionGate) *Reconciler {
+	r.deletionGate = fn
+	return r
+}
+
+func (r *Reconciler) WithObjectValidator(fn ObjectValidator) *Reconciler {
+	r.validator = fn
+	return r
+}
+
+func (r *Reconciler) WithMetaCloner(fn MetaCloner) *Reconciler {
+	r.metaCloner = fn
+	return r
+}
+
+func (r *Reconciler) WithCompletionEvent() *Reconciler {
+	r.completionEvents = true
+	return r
+}
+
+// WithPreFetchedObject reuses the object stored under PreFetchedObjectContextDataKey by a parent reconcile instead of
+// fetching it again. Freshness of that object is the responsibility of the parent: a stale object is reconciled as is
+// and its metadata and status are patched on top of it, see PreFetchedObjectContextDataKey.
+func (r *Reconciler) WithPreFetchedObject() *Reconciler {
+	r.preFetched = true
+	return r
+}
+
 func (r *Reconciler) WithWebhooks() *Reconciler {
 	r.webhooksEnabled = true
 	return r
 }
 
+// GetComponent returns the component registered under name.
+func (r *Reconciler) GetComponent(name string) (Component, bool) {
+	for _, rc := range r.components {
+		if rc.name == name {
+			return rc.comp, true
+		}
+	}
+
+	return nil, false
+}
+
+// WatchedKinds returns the kinds watched through For, Component, OwnsIfAvailable and the Watches* methods once the
+// controller has been built. Watches that initializer components register on the builder directly are not included.
+func (r *Reconciler) WatchedKinds() []schema.GroupVersionKind {
+	return append([]schema.GroupVersionKind(nil), r.watchedGVKs...)
+}
+
+// ComponentOrder returns the names of the registered components in the order they are reconciled.
+func (r *Reconciler) ComponentOrder() []string {
+	names := make([]string, 0, len(r.components))
+	for _, rc := range r.components {
+		names = append(names, rc.name)
+	}
+
+	return names
+}
+
 func (r *Reconciler) Build() (controller.Controller, error) {
+	for _, fn := range r.addToScheme {
+		if err := fn(r.mgr.GetScheme()); err != nil {
+			return nil, fmt.Errorf("cannot register types with scheme: %w", err)
+		}
+	}
+
 	name, err := r.getControllerName()
 	if err != nil {
 		return nil, fmt.Errorf("cannot compute controller name: %w", err)
 	}
 	r.name = name
 	r.log = ctrl.Log.WithName("controller").WithName(name)
-	r.recorder = r.mgr.GetEventRecorderFor(fmt.Sprintf("%s-%s", r.name, "controller"))
+	eventSource := r.eventSource
+	if eventSource == "" {
+		eventSource = fmt.Sprintf("%s-%s", r.name, "controller")
+	}
+	r.recorder = newSafeRecorder(r.mgr.GetEventRecorderFor(eventSource), r.mgr.GetScheme(), r.log)
+	if r.compRecorders {
+		for _, rc := range r.components {
+			source := fmt.Sprintf("%s-%s", r.name, rc.name)
+			rc.recorder = newSafeRecorder(r.mgr.GetEventRecorderFor(source), r.mgr.GetScheme(), r.log)
+		}
+	}
 
 	gvk, err := getGvk(r.apiType, r.mgr.GetScheme())
 	if err != nil {
@@ -129,6 +714,17 @@ func (r *Reconciler) Build() (controller.Controller, error) {
 
 	// resource name should reference api type regardless of controller name
 	r.resourceName = strings.ToLower(gvk.Kind)
+	r.resourceGVK = gvk
+
+	if r.metaCloner != nil {
+		if probe := r.metaCloner(r.apiType); probe == nil || probe == r.apiType {
+			return nil, fmt.Errorf("meta cloner must return an independent copy of %T", r.apiType)
+		}
+	}
+
+	if !hasStatusField(r.apiType) {
+		r.statusDisabled = true
+	}
 
 	// configure finalizer base path and patcher
 	if r.finalizerBaseName == "" {
@@ -138,22 +734,92 @@ func (r *Reconciler) Build() (controller.Controller, error) {
 		r.patcher = NewPatch(gvk)
 	}
 
+	if len(r.warmupGVKs) > 0 {
+		warmup := &cacheWarmup{cache: r.mgr.GetCache(), gvks: r.warmupGVKs}
+		if err = r.mgr.Add(warmup); err != nil {
+			return nil, fmt.Errorf("cannot add cache warmup: %w", err)
+		}
+		if err = r.mgr.AddReadyzCheck(fmt.Sprintf("%s-cache-warmup", name), warmup.Check); err != nil {
+			return nil, fmt.Errorf("cannot add cache warmup readiness check: %w", err)
+		}
+	}
+
+	for _, o := range r.optionalOwns {
+		ownedGVK, err := getGvk(o.obj, r.mgr.GetScheme())
+		if err != nil {
+			return nil, fmt.Errorf("cannot get GVK for object %#v: %w", o.obj, err)
+		}
+		if _, err = r.mgr.GetRESTMapper().RESTMapping(ownedGVK.GroupKind(), ownedGVK.Version); err != nil {
+			if !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("cannot discover api type %s: %w", ownedGVK, err)
+			}
+			r.log.Info("Not watching owned objects, api type is not served by the cluster", "gvk", ownedGVK)
+			continue
+		}
+		r.controllerBuilder.Owns(o.obj, o.opts...)
+		r.watched = append(r.watched, o.obj)
+	}
+
+	seen := map[schema.GroupVersionKind]struct{}{}
+	for _, obj := range r.watched {
+		watchedGVK, err := getGvk(obj, r.mgr.GetScheme())
+		if err != nil {
+			return nil, fmt.Errorf("cannot get GVK for object %#v: %w", obj, err)
+		}
+		if _, ok := seen[watchedGVK]; !ok {
+			seen[watchedGVK] = struct{}{}
+			r.watchedGVKs = append(r.watchedGVKs, watchedGVK)
+		}
+	}
+
+	// register field indexes ahead of initializer components
+	for _, idx := range r.indexes {
+		// indexes registered without an object apply to the reconciled api type
+		if idx.obj == nil {
+			idx.obj = r.apiType
+		}
+		if err = r.mgr.GetFieldIndexer().IndexField(context.Background(), idx.obj, idx.field, idx.extractor); err != nil {
+			return nil, fmt.Errorf("cannot register index %s for object %T: %w", idx.field, idx.obj, err)
+		}
+	}
+
 	// minimal context for initializer components (if any)
 	initCtx := &Context{
-		Context: context.Background(),
-		Client:  r.client,
-		Scheme:  r.mgr.GetScheme(),
-		Data:    r.contextData,
+		Context:    context.Background(),
+		Config:     r.config,
+		Client:     r.client,
+		Scheme:     r.mgr.GetScheme(),
+		Data:       r.contextData,
+		Metadata:   r.metadata,
+		RESTMapper: r.mgr.GetRESTMapper(),
+		clientset:  r.clientset,
 	}
 	initLog := r.log.WithName("component")
 
+	namePolicy := r.namePolicy
+	if namePolicy == nil {
+		namePolicy = DefaultComponentNamePolicy
+	}
+
 	components := map[string]Component{}
 	for _, rc := range r.components {
 		orig, ok := components[rc.name]
 		if ok {
 			return nil, fmt.Errorf("duplicate component found using name %s: %#v %#v", rc.name, orig, rc.comp)
 		}
+		if !namePolicy.MatchString(rc.name) {
+			return nil, fmt.Errorf("component name %q does not match naming policy %s", rc.name, namePolicy)
+		}
 		rc.finalizerName = path.Join(r.finalizerBaseName, rc.name)
+		if rc.multiFinalizer != nil {
+			rc.multiFinalizers = nil
+			for _, name := range rc.multiFinalizer.Finalizers() {
+				rc.multiFinalizers = append(rc.multiFinalizers, namedFinalizer{
+					name:      name,
+					finalizer: path.Join(r.finalizerBaseName, fmt.Sprintf("%s-%s", rc.name, name)),
+				})
+			}
+		}
 
 		components[rc.name] = rc.comp
 
@@ -168,6 +834,8 @@ func (r *Reconciler) Build() (controller.Controller, error) {
 		}
 	}
 
+	r.log.V(1).Info("Registered components", "order", r.ComponentOrder())
+
 	r.controller, err = r.controllerBuilder.Build(r)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build controller: %w", err)
@@ -190,15 +858,36 @@ func (r *Reconciler) Complete() error {
 }
 
 func (r *Reconciler) Reconcile(rootCtx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.log.WithValues(r.resourceName, req.NamespacedName)
-	log.Info("Starting reconcile")
+	log := r.log
+	if r.logConstructor != nil {
+		log = r.logConstructor(&req)
+	}
+	log = log.WithValues(r.resourceName, req.NamespacedName)
+	if r.logGVK {
+		log = log.WithValues("gvk", r.resourceGVK.String())
+	}
+	bracketLog := log
+	if r.quietLogs {
+		bracketLog = log.V(1)
+	}
+	bracketLog.Info("Starting reconcile")
 
-	// fetch event api object
-	obj := r.apiType.DeepCopyObject().(client.Object)
-	if err := r.client.Get(rootCtx, req.NamespacedName, obj); err != nil {
+	// drain recorded triggers up front so that reconciles ending early do not leave them behind
+	triggers := r.triggers.pop(req.NamespacedName)
+
+	// fetch event api object unless a valid one was handed over by a parent reconcile
+	found := true
+	obj, preFetched := r.preFetchedObject(rootCtx, req)
+	if preFetched {
+		log.V(1).Info("Using pre-fetched object", "resourceVersion", obj.GetResourceVersion())
+	} else if err := r.client.Get(rootCtx, req.NamespacedName, obj); err != nil {
 		if !apierrors.IsNotFound(err) {
-			log.Error(err, "Failed to fetch reconcile object")
-			return ctrl.Result{}, err
+			return ctrl.Result{}, r.handleFetchError(req, obj, err, log)
+		}
+		r.clearTerminating(req.NamespacedName)
+		r.failures.Delete(req.NamespacedName)
+		if r.conditionMetrics {
+			r.forgetConditionMetrics(req.NamespacedName)
 		}
 
 		if r.abortNotFound {
@@ -206,101 +895,479 @@ func (r *Reconciler) Reconcile(rootCtx context.Context, req ctrl.Request) (ctrl.
 			return ctrl.Result{}, nil
 		}
 
+		// synthesize an object carrying only the request key, the generated name is the only valid identity
+		found = false
 		obj.SetName(req.Name)
 		obj.SetNamespace(req.Namespace)
+		obj.SetGenerateName("")
 	}
 	cleanObj := obj.DeepCopyObject().(client.Object)
 
-	// skip reconcile when annotated
-	skip, ok := obj.GetAnnotations()[SkipReconcileAnnotation]
-	if ok && skip == "true" {
-		log.Info("Skipping reconcile due to annotation")
+	if r.reconcileFilter != nil && !r.reconcileFilter(obj) {
+		bracketLog.Info("Reconciliation complete, object filtered out")
+		r.recordSkip(skipReasonFilter)
 		return ctrl.Result{}, nil
 	}
 
-	// build context for components
-	compLog := log.WithName("component")
-	ctx := &Context{
-		Context:    rootCtx,
-		Object:     obj,
-		Config:     r.config,
-		Client:     r.client,
-		Patch:      r.patcher,
-		Scheme:     r.mgr.GetScheme(),
-		Recorder:   r.recorder,
-		Conditions: NewConditionHelper(obj),
-		Data:       r.contextData,
+	if obj.GetDeletionTimestamp().IsZero() {
+		r.clearTerminating(req.NamespacedName)
+	} else {
+		r.markTerminating(req.NamespacedName)
 	}
 
-	// reconcile components
-	var finalRes ctrl.Result
-	var errs []error
-	for _, rc := range r.components {
+	// skip reconcile when annotated or when the skip predicate matches
+	skipped, reason, err := r.shouldSkip(rootCtx, obj)
+	if err != nil {
+		log.Error(err, "Failed to evaluate skip conditions")
+		return ctrl.Result{}, err
+	}
+	if skipped != "" {
+		log.Info("Skipping reconcile " + reason)
+		r.recordSkip(skipped)
+		return ctrl.Result{}, r.pause(rootCtx, req.NamespacedName, obj, cleanObj, reason)
+	}
+
+	if r.defaulting && found {
+		if obj, err = r.applyDefaults(rootCtx, obj); err != nil {
+			log.Error(err, "Failed to persist defaulted object")
+			return ctrl.Result{}, err
+		}
+		cleanObj = obj.DeepCopyObject().(client.Object)
+	}
+
+	data, err := r.reconcileData(rootCtx, obj, triggers)
+	if err != nil {
+		log.Error(err, "Failed to compute context data")
+		return ctrl.Result{}, err
+	}
+
+	// build context for components
+	compLog := log.WithName("component")
+	ctx := &Context{
+		Context:      rootCtx,
+		Object:       obj,
+		Original:     cleanObj.DeepCopyObject().(client.Object),
+		Config:       r.config,
+		Client:       r.client,
+		Patch:        r.patcher,
+		Scheme:       r.mgr.GetScheme(),
+		Recorder:     r.recorder,
+		Conditions:   NewConditionHelperWithAccessor(obj, r.conditions),
+		Data:         data,
+		Metadata:     r.metadata,
+		RESTMapper:   r.mgr.GetRESTMapper(),
+		clientset:    r.clientset,
+		specRequeue:  r.specRequeue,
+		fieldManager: r.name,
+		elected:      r.mgr.Elected(),
+	}
+
+	ctx.Conditions.log = log
+	ctx.Conditions.allowedTypes = r.allowedConditions
+	ctx.Conditions.strictTypes = r.strictConditions
+
+	// clear paused condition once reconciliation resumes
+	r.pausedObjs.Delete(req.NamespacedName)
+	if conditions := r.conditions(obj); conditions != nil {
+		RemoveStatusCondition(conditions, ReconciliationConditionType)
+	}
+
+	// reconcile components
+	var finalRes ctrl.Result
+	var errs []error
+	var finalizing []string
+	identityChanged := false
+	summary := newComponentSummary()
+	ctx.Data[ComponentSummaryContextDataKey] = summary
+
+	valid := true
+	if r.validator != nil && !r.isDeleting(ctx.Object) {
+		valid = r.validate(ctx, log)
+	}
+
+	blocked := false
+	if r.deletionGate != nil && r.isDeleting(ctx.Object) {
+		var err error
+		if blocked, err = r.gateDeletion(ctx, log); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	aborted := func() bool { return false }
+	if r.abortOnSpecChange && found && !r.isDeleting(ctx.Object) {
+		var stop context.CancelFunc
+		ctx.Context, aborted, stop = r.watchSpecChange(rootCtx, req, obj.GetGeneration(), log)
+		defer stop()
+	}
+
+	// suspended objects still register and run finalizers, only regular component work is held back
+	suspended := r.suspendField != nil && !r.isDeleting(ctx.Object) && r.suspendField(ctx.Object)
+	if suspended {
+		log.Info("Object is suspended, skipping component reconciliation")
+		ctx.Conditions.SetTrue(SuspendedConditionType, SuspendedReason, "Component reconciliation is suspended")
+		ctx.Conditions.Flush()
+	} else if r.suspendField != nil {
+		if conditions := r.conditions(ctx.Object); conditions != nil {
+			RemoveStatusCondition(conditions, SuspendedConditionType)
+		}
+	}
+
+	unchanged := false
+	if r.rvSkip && found {
+		lastRV, ok := r.reconciledRVs.LoadAndDelete(req.NamespacedName)
+		unchanged = ok && lastRV == obj.GetResourceVersion()
+	}
+	// a one-time requeue armed for the current resourceVersion must not arm itself again. the marker is kept for as
+	// long as the object is unchanged so that later reconciles, e.g. resyncs, do not arm it either.
+	requeueOnceArmed := false
+	if armedRV, ok := r.requeuedOnce.Load(req.NamespacedName); ok {
+		if requeueOnceArmed = found && armedRV == obj.GetResourceVersion(); !requeueOnceArmed {
+			r.requeuedOnce.Delete(req.NamespacedName)
+		}
+	}
+
+	sameGeneration := false
+	if r.generationSkip && found {
+		lastGen, ok := r.reconciledGens.LoadAndDelete(req.NamespacedName)
+		sameGeneration = ok && lastGen == obj.GetGeneration()
+	}
+
+	for _, rc := range r.components {
 		res := ctrl.Result{}
 		var err error
 
+		if !valid {
+			summary.skipped(rc.name, "object is invalid")
+			continue
+		}
+		if aborted() {
+			summary.skipped(rc.name, "spec changed during reconcile")
+			continue
+		}
+		if rootCtx.Err() != nil {
+			summary.skipped(rc.name, "reconcile cancelled")
+			continue
+		}
+		if blocked {
+			summary.skipped(rc.name, "deletion blocked")
+			continue
+		}
+		if unchanged && !r.isDeleting(ctx.Object) {
+			if skipComp, ok := rc.comp.(SkipUnchangedComponent); ok && skipComp.SkipUnchanged() {
+				summary.skipped(rc.name, "resource version unchanged")
+				continue
+			}
+		}
+		if sameGeneration && !r.isDeleting(ctx.Object) {
+			if statusComp, ok := rc.comp.(StatusOnlyComponent); ok && statusComp.StatusOnly() {
+				summary.skipped(rc.name, "generation unchanged")
+				continue
+			}
+		}
+		migration, isMigration := rc.comp.(MigrationComponent)
+		if isMigration && !r.isDeleting(ctx.Object) &&
+			ctx.Object.GetAnnotations()[MigrationAnnotationPrefix+rc.name] == migration.MigrationVersion() {
+			summary.skipped(rc.name, "migration already applied")
+			continue
+		}
 		ctx.Log = compLog.WithName(rc.name)
+		if r.conditionPrefix {
+			ctx.Conditions.prefix = rc.name + "."
+		}
+		if r.componentConditions != nil {
+			ctx.Conditions.accessor = r.componentConditionsAccessor(rc.name)
+		}
+		if rc.recorder != nil {
+			ctx.Recorder = rc.recorder
+		}
 
-		if ctx.Object.GetDeletionTimestamp().IsZero() {
-			log.Info("Reconciling component", "component", rc.name)
-			res, err = rc.comp.Reconcile(ctx)
+		if !r.isDeleting(ctx.Object) {
+			if suspended {
+				summary.skipped(rc.name, "object is suspended")
+			} else {
+				log.Info("Reconciling component", "component", rc.name)
+				if reporter, ok := rc.comp.(ConditionReportingComponent); ok {
+					var conds []metav1.Condition
+					conds, res, err = reporter.ReconcileConditions(ctx)
+					for _, cond := range conds {
+						ctx.Conditions.SetCondition(cond)
+					}
+				} else {
+					res, err = rc.comp.Reconcile(ctx)
+				}
+				summary.executed(rc.name)
+			}
+
+			if isMigration && !suspended && err == nil && res.IsZero() {
+				log.Info("Recording completed migration", "component", rc.name, "version", migration.MigrationVersion())
+				annotations := ctx.Object.GetAnnotations()
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[MigrationAnnotationPrefix+rc.name] = migration.MigrationVersion()
+				ctx.Object.SetAnnotations(annotations)
+			}
 
 			if rc.finalizer != nil && !controllerutil.ContainsFinalizer(ctx.Object, rc.finalizerName) {
 				log.Info("Registering finalizer", "component", rc.name)
 				controllerutil.AddFinalizer(ctx.Object, rc.finalizerName)
 			}
-		} else if rc.finalizer != nil && controllerutil.ContainsFinalizer(ctx.Object, rc.finalizerName) {
+			for _, nf := range rc.multiFinalizers {
+				if !controllerutil.ContainsFinalizer(ctx.Object, nf.finalizer) {
+					log.Info("Registering finalizer", "component", rc.name, "finalizer", nf.name)
+					controllerutil.AddFinalizer(ctx.Object, nf.finalizer)
+				}
+			}
+		} else if rc.hasPendingFinalizer(ctx.Object) {
 			log.Info("Finalizing component", "component", rc.name)
-
-			var done bool
-			res, done, err = rc.finalizer.Finalize(ctx)
-			if done {
-				log.Info("Removing finalizer", "component", rc.name)
-				controllerutil.RemoveFinalizer(ctx.Object, rc.finalizerName)
+			var progress string
+			res, progress, err = r.finalize(ctx, rc, log)
+			if progress != "" {
+				finalizing = append(finalizing, fmt.Sprintf("%s: %s", rc.name, progress))
 			}
+			summary.executed(rc.name)
+		} else if rc.finalizer == nil && rc.multiFinalizer == nil {
+			summary.skipped(rc.name, "object is being deleted")
+		} else {
+			summary.skipped(rc.name, "finalizer already removed")
 		}
 
-		ctx.Conditions.Flush()
-		if res.Requeue {
-			finalRes.Requeue = true
+		if flushErr := ctx.Conditions.Flush(); flushErr != nil {
+			err = utilerrors.NewAggregate([]error{err, flushErr})
 		}
-		if res.RequeueAfter != 0 && (finalRes.RequeueAfter == 0 || finalRes.RequeueAfter > res.RequeueAfter) {
-			finalRes.RequeueAfter = res.RequeueAfter
+		ctx.Conditions.prefix = ""
+		ctx.Conditions.accessor = r.conditions
+		ctx.Recorder = r.recorder
+		finalRes = mergeResults(finalRes, res)
+
+		// restore identity fields so that a misbehaving component cannot redirect the metadata and status patches.
+		// the object is not patched at all since other changes made by the component cannot be trusted either.
+		if key := client.ObjectKeyFromObject(ctx.Object); key != req.NamespacedName {
+			idErr := fmt.Errorf("component %s changed object identity from %s to %s", rc.name, req.NamespacedName, key)
+			log.Error(idErr, "Restoring object identity", "component", rc.name)
+			errs = append(errs, idErr)
+			identityChanged = true
+
+			ctx.Object.SetName(req.Name)
+			ctx.Object.SetNamespace(req.Namespace)
 		}
-		if err != nil {
+		if err != nil && !(aborted() && errors.Is(err, context.Canceled)) {
 			log.Error(err, "Component reconciliation failed", "component", rc.name)
+			errs = append(errs, fmt.Errorf("component %q: %w", rc.name, err))
+		}
+	}
+
+	if len(finalizing) > 0 {
+		ctx.Conditions.SetTrue(FinalizingConditionType, FinalizingInProgressReason, strings.Join(finalizing, "; "))
+		if err := ctx.Conditions.Flush(); err != nil {
 			errs = append(errs, err)
 		}
 	}
+	errClass := r.classifyErrors(errs)
+	// transient errors neither count towards the stalled threshold nor reset it, only a reconcile that ran its
+	// components without errors does
+	if r.stalledAfter > 0 {
+		switch {
+		case len(errs) > 0 && errClass != ErrorClassTransient:
+			r.trackFailures(ctx, req, true, log)
+		case len(errs) == 0 && valid && !blocked && !suspended && !aborted() && rootCtx.Err() == nil:
+			r.trackFailures(ctx, req, false, log)
+		}
+	}
+	if found && r.errorClassifier != nil {
+		r.reportTerminalErrors(ctx, errs, errClass)
+	}
+	if r.specRequeue != nil && !r.isDeleting(ctx.Object) {
+		if interval, ok := ctx.SpecRequeueInterval(); ok {
+			finalRes = mergeResults(finalRes, ctrl.Result{RequeueAfter: interval})
+		}
+	}
+	if r.postRequeue > 0 && len(errs) == 0 && !r.isDeleting(ctx.Object) {
+		finalRes = mergeResults(finalRes, ctrl.Result{RequeueAfter: r.postRequeue})
+	}
+	if r.conditionRequeue != nil {
+		finalRes = r.applyConditionRequeue(req.NamespacedName, ctx.Object, finalRes)
+	}
+	if aborted() || blocked {
+		finalRes = ctrl.Result{Requeue: true}
+	}
+	ctx.Context = rootCtx
+
+	// a synthesized object does not exist in the cluster, hence there is nothing to patch
+	if !found {
+		bracketLog.Info("Reconciliation complete, object not found", "executed", summary.Executed, "skipped", summary.Skipped)
+		return classifiedResult(finalRes, errClass, r.aggregateErrors(errs))
+	}
+	if identityChanged {
+		bracketLog.Info("Reconciliation complete, object identity changed, skipped patches", "executed", summary.Executed, "skipped", summary.Skipped)
+		return classifiedResult(finalRes, errClass, r.aggregateErrors(errs))
+	}
+
+	if r.reconcileStatus && !r.statusDisabled {
+		recordReconcileStatus(ctx.Object, finalRes, r.aggregateErrors(errs))
+	}
 
 	// patch metadata and status when changes occur
-	currentMeta := r.apiType.DeepCopyObject().(client.Object)
+	currentMeta := r.cloneMeta(r.apiType)
 	currentMeta.SetName(ctx.Object.GetName())
 	currentMeta.SetNamespace(ctx.Object.GetNamespace())
-	currentMeta.SetLabels(ctx.Object.GetLabels())
-	currentMeta.SetAnnotations(ctx.Object.GetAnnotations())
-	currentMeta.SetFinalizers(ctx.Object.GetFinalizers())
+	currentMeta.SetLabels(withoutExcludedKeys(ctx.Object.GetLabels(), cleanObj.GetLabels(), r.excludedLabels))
+	currentMeta.SetAnnotations(withoutExcludedKeys(ctx.Object.GetAnnotations(), cleanObj.GetAnnotations(), r.excludedAnnotations))
 
-	cleanMeta := r.apiType.DeepCopyObject().(client.Object)
+	cleanMeta := r.cloneMeta(r.apiType)
 	cleanMeta.SetName(cleanObj.GetName())
 	cleanMeta.SetNamespace(cleanObj.GetNamespace())
 	cleanMeta.SetLabels(cleanObj.GetLabels())
 	cleanMeta.SetAnnotations(cleanObj.GetAnnotations())
-	cleanMeta.SetFinalizers(cleanObj.GetFinalizers())
+
+	// finalizers are patched separately using an optimistic lock so that concurrent finalizer changes made by other
+	// controllers are never overwritten. they are captured before any patch since the status patch decodes the stored
+	// object into ctx.Object.
+	finalizers := append([]string(nil), ctx.Object.GetFinalizers()...)
+	finalizersChanged := !equality.Semantic.DeepEqual(finalizers, cleanObj.GetFinalizers())
+
+	if r.mutationGuard {
+		r.guardMutations(ctx.Object, cleanObj, currentMeta, cleanMeta, log)
+	}
+	if r.diffReporter != nil {
+		if finalizersChanged {
+			currentFin, finPatch := r.finalizerPatch(cleanObj, cleanObj.GetFinalizers(), finalizers)
+			r.reportDiff("finalizers", finPatch, currentFin, log)
+		}
+		r.reportDiff("metadata", client.MergeFrom(cleanMeta), currentMeta, log)
+		if !r.statusDisabled {
+			r.reportDiff("status", client.MergeFrom(cleanObj), ctx.Object, log)
+		}
+	}
+	if r.dryRun {
+		bracketLog.Info("Reconciliation complete, dry run skipped patches", "executed", summary.Executed, "skipped", summary.Skipped)
+		return classifiedResult(finalRes, errClass, r.aggregateErrors(errs))
+	}
 
 	patchOpts := &client.PatchOptions{FieldManager: r.name}
+	var patchCtx context.Context = ctx
+	if r.drainTimeout > 0 && rootCtx.Err() != nil {
+		log.Info("Reconcile interrupted by shutdown, draining patches", "timeout", r.drainTimeout)
+		var cancel context.CancelFunc
+		patchCtx, cancel = context.WithTimeout(context.Background(), r.drainTimeout)
+		defer cancel()
+	}
+
+	// ignore NotFound errors when patching object/status since the object may already be deleted. a failing patch does
+	// not prevent the remaining ones, so that e.g. a rejected status does not drop new finalizers.
+	var patchErrs []error
+	if err := r.client.Patch(patchCtx, currentMeta, client.MergeFrom(cleanMeta), patchOpts); err != nil && !apierrors.IsNotFound(err) {
+		patchErrs = append(patchErrs, fmt.Errorf("error patching metadata: %w", err))
+	}
+	patchedRV := currentMeta.GetResourceVersion()
+
+	// the metadata patch bumps the resourceVersion, hence the finalizer changes are rebased onto the object it returned
+	if finalizersChanged && patchedRV != "" {
+		currentFin, finPatch := r.finalizerPatch(currentMeta, cleanObj.GetFinalizers(), finalizers)
+		if !equality.Semantic.DeepEqual(currentFin.GetFinalizers(), currentMeta.GetFinalizers()) {
+			if err := r.client.Patch(patchCtx, currentFin, finPatch, patchOpts); err != nil && !apierrors.IsNotFound(err) {
+				patchErrs = append(patchErrs, fmt.Errorf("error patching finalizers: %w", err))
+			} else if rv := currentFin.GetResourceVersion(); rv != "" {
+				patchedRV = rv
+			}
+		}
+	}
 
-	// ignore NotFound errors when patching object/status since the object may already be deleted
-	if err := r.client.Patch(ctx, currentMeta, client.MergeFrom(cleanMeta), patchOpts); err != nil && !apierrors.IsNotFound(err) {
-		return ctrl.Result{}, fmt.Errorf("error patching metadata: %w", err)
+	if r.statusDisabled {
+		log.V(1).Info("Skipping status patch, api type has no status")
+	} else if err := r.client.Status().Patch(patchCtx, ctx.Object, client.MergeFrom(cleanObj)); err != nil && !apierrors.IsNotFound(err) {
+		patchErrs = append(patchErrs, fmt.Errorf("error patching status: %w", err))
+	} else if rv := ctx.Object.GetResourceVersion(); rv != "" && rv != cleanObj.GetResourceVersion() {
+		patchedRV = rv
 	}
-	if err := r.client.Status().Patch(ctx, ctx.Object, client.MergeFrom(cleanObj)); err != nil && !apierrors.IsNotFound(err) {
-		return ctrl.Result{}, fmt.Errorf("error patching status: %w", err)
+	if len(patchErrs) > 0 {
+		return ctrl.Result{}, utilerrors.NewAggregate(patchErrs)
+	}
+
+	if !ctx.Object.GetDeletionTimestamp().IsZero() && len(finalizers) == 0 {
+		r.clearTerminating(req.NamespacedName)
+	}
+	if r.conditionMetrics {
+		if conditions := r.conditions(ctx.Object); conditions != nil {
+			r.recordConditionMetrics(req.NamespacedName, *conditions)
+		}
+	}
+	if r.rvSkip && len(errs) == 0 && finalRes.IsZero() {
+		r.reconciledRVs.Store(req.NamespacedName, patchedRV)
+	}
+	if r.generationSkip && len(errs) == 0 && finalRes.IsZero() {
+		r.reconciledGens.Store(req.NamespacedName, ctx.Object.GetGeneration())
+	}
+	switch {
+	case requeueOnceArmed:
+		// writes made by this reconcile do not count as changes of the object
+		r.requeuedOnce.Store(req.NamespacedName, patchedRV)
+		if ctx.requeueOnce > 0 {
+			log.V(1).Info("Ignoring one-time requeue, object unchanged since it was scheduled")
+		}
+	case ctx.requeueOnce > 0:
+		finalRes = mergeResults(finalRes, ctrl.Result{RequeueAfter: ctx.requeueOnce})
+		r.requeuedOnce.Store(req.NamespacedName, patchedRV)
 	}
 
 	// condense all error messages into one
-	log.Info("Reconciliation complete")
-	return finalRes, utilerrors.NewAggregate(errs)
+	bracketLog.Info("Reconciliation complete", "executed", summary.Executed, "skipped", summary.Skipped)
+	aggErr := r.aggregateErrors(errs)
+
+	if r.completionEvents {
+		if aggErr != nil {
+			r.recorder.Event(ctx.Object, corev1.EventTypeWarning, "ReconcileFailed", aggErr.Error())
+		} else {
+			r.recorder.Event(ctx.Object, corev1.EventTypeNormal, "ReconcileSucceeded", "Reconciliation completed successfully")
+		}
+	}
+
+	return classifiedResult(finalRes, errClass, aggErr)
+}
+
+// finalizerPatch returns an object carrying the finalizers of base with the finalizers added and removed between clean
+// and current applied, along with an optimistic lock patch against base.
+func (r *Reconciler) finalizerPatch(base client.Object, clean, current []string) (client.Object, client.Patch) {
+	removed := make(map[string]bool, len(clean))
+	for _, f := range clean {
+		removed[f] = true
+	}
+	for _, f := range current {
+		delete(removed, f)
+	}
+
+	var finalizers []string
+	seen := map[string]bool{}
+	for _, f := range append(append([]string(nil), base.GetFinalizers()...), current...) {
+		if !removed[f] && !seen[f] {
+			finalizers = append(finalizers, f)
+			seen[f] = true
+		}
+	}
+
+	baseFin := r.cloneMeta(r.apiType)
+	baseFin.SetName(base.GetName())
+	baseFin.SetNamespace(base.GetNamespace())
+	baseFin.SetResourceVersion(base.GetResourceVersion())
+	baseFin.SetFinalizers(base.GetFinalizers())
+
+	currentFin := r.cloneMeta(r.apiType)
+	currentFin.SetName(base.GetName())
+	currentFin.SetNamespace(base.GetNamespace())
+	currentFin.SetResourceVersion(base.GetResourceVersion())
+	currentFin.SetFinalizers(finalizers)
+	if r.finalizeTransform != nil {
+		r.finalizeTransform(currentFin)
+	}
+
+	return currentFin, client.MergeFromWithOptions(baseFin, client.MergeFromWithOptimisticLock{})
+}
+
+func (r *Reconciler) cloneMeta(obj client.Object) client.Object {
+	if r.metaCloner != nil {
+		return r.metaCloner(obj)
+	}
+	return obj.DeepCopyObject().(client.Object)
 }
 
 func (r *Reconciler) getControllerName() (string, error) {
@@ -315,3 +1382,456 @@ func (r *Reconciler) getControllerName() (string, error) {
 
 	return strings.ToLower(gvk.Kind), nil
 }
+
+// preFetchedObject returns a copy of the object stored under PreFetchedObjectContextDataKey when the parent context is
+// a *Context and the object matches the request. Otherwise, an empty object of the api type is returned. The
+// resourceVersion is only checked for presence, see PreFetchedObjectContextDataKey.
+func (r *Reconciler) preFetchedObject(rootCtx context.Context, req ctrl.Request) (client.Object, bool) {
+	empty := r.apiType.DeepCopyObject().(client.Object)
+	if !r.preFetched {
+		return empty, false
+	}
+
+	parent, ok := rootCtx.(*Context)
+	if !ok {
+		return empty, false
+	}
+	obj, ok := parent.Data[PreFetchedObjectContextDataKey].(client.Object)
+	if !ok || reflect.TypeOf(obj) != reflect.TypeOf(r.apiType) {
+		return empty, false
+	}
+	if client.ObjectKeyFromObject(obj) != req.NamespacedName || obj.GetResourceVersion() == "" {
+		return empty, false
+	}
+
+	return obj.DeepCopyObject().(client.Object), true
+}
+
+// handleFetchError logs a failure to get the reconcile object and, for objects last seen pending deletion, records an
+// event since finalizers cannot make progress until the object can be read again.
+func (r *Reconciler) handleFetchError(req ctrl.Request, obj client.Object, err error, log logr.Logger) error {
+	if apierrors.IsForbidden(err) {
+		err = fmt.Errorf("controller %s is forbidden from getting %s %s, verify its RBAC permissions: %w", r.name, r.resourceName, req.NamespacedName, err)
+	}
+
+	if _, ok := r.terminating.Load(req.NamespacedName); !ok {
+		log.Error(err, "Failed to fetch reconcile object")
+		return err
+	}
+
+	log.Error(err, "Failed to fetch reconcile object pending deletion, finalizers are blocked until it can be read")
+	obj.SetName(req.Name)
+	obj.SetNamespace(req.Namespace)
+	r.recorder.Event(obj, corev1.EventTypeWarning, "FetchFailed", err.Error())
+
+	return err
+}
+
+// applyConditionRequeue applies the requeue intervals configured for the reasons of non-True conditions. The number of
+// consecutive reconciles each condition type stayed non-True is tracked per object to back off the interval.
+func (r *Reconciler) applyConditionRequeue(key client.ObjectKey, obj client.Object, res ctrl.Result) ctrl.Result {
+	conditions := r.conditions(obj)
+	if conditions == nil {
+		return res
+	}
+
+	var previous map[string]int
+	if v, ok := r.conditionBackoff.Load(key); ok {
+		previous = v.(map[string]int)
+	}
+	attempts := map[string]int{}
+	defer func() {
+		if len(attempts) == 0 {
+			r.conditionBackoff.Delete(key)
+		} else {
+			r.conditionBackoff.Store(key, attempts)
+		}
+	}()
+
+	for _, cond := range *conditions {
+		if cond.Status == metav1.ConditionTrue {
+			continue
+		}
+
+		interval, ok := r.conditionRequeue[cond.Reason]
+		if !ok {
+			continue
+		}
+		if interval <= 0 {
+			attempts = nil
+			return ctrl.Result{}
+		}
+
+		n := previous[cond.Type]
+		attempts[cond.Type] = n + 1
+		for ; n > 0 && interval < MaxConditionRequeueInterval; n-- {
+			interval *= 2
+		}
+		if interval > MaxConditionRequeueInterval {
+			interval = MaxConditionRequeueInterval
+		}
+		if res.RequeueAfter == 0 || res.RequeueAfter > interval {
+			res.RequeueAfter = interval
+		}
+	}
+
+	return res
+}
+
+func (r *Reconciler) reportDiff(kind string, patch client.Patch, obj client.Object, log logr.Logger) {
+	diff, err := patch.Data(obj)
+	if err != nil {
+		log.Error(err, "Cannot compute diff", "kind", kind)
+		return
+	}
+	if len(diff) == 0 || string(diff) == "{}" {
+		return
+	}
+
+	log.V(1).Info("Computed reconcile diff", "kind", kind, "diff", string(diff))
+	r.diffReporter(kind, diff)
+}
+
+// guardMutations warns about status changes that would be silently dropped or sent with the metadata patch.
+func (r *Reconciler) guardMutations(obj, cleanObj, currentMeta, cleanMeta client.Object, log logr.Logger) {
+	if r.statusDisabled {
+		current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			log.Error(err, "Cannot inspect object status")
+			return
+		}
+		clean, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cleanObj)
+		if err != nil {
+			log.Error(err, "Cannot inspect object status")
+			return
+		}
+		if !equality.Semantic.DeepEqual(current["status"], clean["status"]) {
+			log.Info("Components changed the object status but status patching is disabled, changes are dropped")
+		}
+	}
+
+	data, err := client.MergeFrom(cleanMeta).Data(currentMeta)
+	if err != nil {
+		log.Error(err, "Cannot compute metadata patch")
+		return
+	}
+	patch := map[string]interface{}{}
+	if err = json.Unmarshal(data, &patch); err != nil {
+		log.Error(err, "Cannot decode metadata patch")
+		return
+	}
+	if _, ok := patch["status"]; ok {
+		log.Info("Metadata patch includes status fields, check the configured meta cloner", "patch", string(data))
+	}
+}
+
+func (r *Reconciler) applyDefaults(ctx context.Context, obj client.Object) (client.Object, error) {
+	defaulted := obj.DeepCopyObject().(client.Object)
+	r.mgr.GetScheme().Default(defaulted)
+	if equality.Semantic.DeepEqual(defaulted, obj) {
+		return obj, nil
+	}
+
+	patchOpts := &client.PatchOptions{FieldManager: r.name}
+	if err := r.client.Patch(ctx, defaulted, client.MergeFrom(obj), patchOpts); err != nil {
+		return nil, err
+	}
+
+	return defaulted, nil
+}
+
+func (r *Reconciler) reconcileData(ctx context.Context, obj client.Object, triggers []Trigger) (ContextData, error) {
+	data := make(ContextData, len(r.contextData)+len(r.dataProviders)+1)
+	for k, v := range r.contextData {
+		data[k] = v
+	}
+	if len(triggers) > 0 {
+		data[TriggersContextDataKey] = triggers
+	}
+
+	for _, p := range r.dataProviders {
+		v, err := p.fn(ctx, obj)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute context data %s: %w", p.key, err)
+		}
+		data[p.key] = v
+	}
+
+	return data, nil
+}
+
+// finalize runs all pending finalizers of a component and removes those that report completion. The progress message
+// of an incomplete FinalizerProgressComponent is returned along with the result.
+func (r *Reconciler) finalize(ctx *Context, rc *reconcilerComponent, log logr.Logger) (ctrl.Result, string, error) {
+	var res ctrl.Result
+	var progress string
+	var errs []error
+
+	if rc.finalizer != nil && controllerutil.ContainsFinalizer(ctx.Object, rc.finalizerName) {
+		var fRes ctrl.Result
+		var done bool
+		var err error
+		if reporter, ok := rc.comp.(FinalizerProgressComponent); ok {
+			var message string
+			if fRes, done, message, err = reporter.FinalizeWithProgress(ctx); !done {
+				progress = message
+			}
+		} else {
+			fRes, done, err = rc.finalizer.Finalize(ctx)
+		}
+		if done {
+			log.Info("Removing finalizer", "component", rc.name)
+			controllerutil.RemoveFinalizer(ctx.Object, rc.finalizerName)
+		}
+		res = mergeResults(res, finalizerRetry(rc, fRes, done))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, nf := range rc.multiFinalizers {
+		if !controllerutil.ContainsFinalizer(ctx.Object, nf.finalizer) {
+			continue
+		}
+
+		fRes, done, err := rc.multiFinalizer.FinalizeNamed(ctx, nf.name)
+		if done {
+			log.Info("Removing finalizer", "component", rc.name, "finalizer", nf.name)
+			controllerutil.RemoveFinalizer(ctx.Object, nf.finalizer)
+		}
+		res = mergeResults(res, finalizerRetry(rc, fRes, done))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("finalizer %s: %w", nf.name, err))
+		}
+	}
+
+	return res, progress, utilerrors.NewAggregate(errs)
+}
+
+// finalizerRetry requeues incomplete finalizers that requested no requeue after the component's retry interval, if
+// any is configured.
+func finalizerRetry(rc *reconcilerComponent, res ctrl.Result, done bool) ctrl.Result {
+	if done || !res.IsZero() {
+		return res
+	}
+
+	interval := FinalizerRetryInterval
+	if retry, ok := rc.comp.(FinalizerRetryComponent); ok {
+		interval = retry.FinalizerRetryInterval()
+	}
+	if interval <= 0 {
+		return res
+	}
+	return ctrl.Result{RequeueAfter: interval}
+}
+
+// componentConditionsAccessor binds the component conditions accessor to a component, falling back to the top-level
+// conditions when the component has no sub-object.
+func (r *Reconciler) componentConditionsAccessor(component string) ConditionsAccessor {
+	return func(obj client.Object) *[]metav1.Condition {
+		if conditions := r.componentConditions(obj, component); conditions != nil {
+			return conditions
+		}
+		return r.conditions(obj)
+	}
+}
+
+func (r *Reconciler) aggregateErrors(errs []error) error {
+	if r.errorAggregator != nil && len(errs) > 0 {
+		return r.errorAggregator(errs)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// isDeleting reports whether the object has a deletion timestamp or carries the soft delete label.
+func (r *Reconciler) isDeleting(obj client.Object) bool {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return true
+	}
+	return r.softDeleteLabel != "" && obj.GetLabels()[r.softDeleteLabel] == "true"
+}
+
+// trackFailures counts consecutive failed reconciles of an object and reports it as stalled at the threshold.
+func (r *Reconciler) trackFailures(ctx *Context, req ctrl.Request, failed bool, log logr.Logger) {
+	if !failed {
+		r.failures.Delete(req.NamespacedName)
+		if conditions := r.conditions(ctx.Object); conditions != nil {
+			RemoveStatusCondition(conditions, StalledConditionType)
+		}
+		return
+	}
+
+	attempts := 1
+	if prev, ok := r.failures.Load(req.NamespacedName); ok {
+		attempts = prev.(int) + 1
+	}
+	r.failures.Store(req.NamespacedName, attempts)
+	if attempts < r.stalledAfter {
+		return
+	}
+
+	message := fmt.Sprintf("Reconciliation failed %d consecutive times", attempts)
+	log.Info("Object is stalled", "attempts", attempts)
+	if attempts == r.stalledAfter {
+		r.recorder.Event(ctx.Object, corev1.EventTypeWarning, "Stalled", message)
+	}
+	ctx.Conditions.SetTrue(StalledConditionType, "MaxRetriesExceeded", message)
+	ctx.Conditions.Flush()
+}
+
+// gateDeletion consults the deletion gate and reports whether finalization is blocked. Errors block finalization.
+func (r *Reconciler) gateDeletion(ctx *Context, log logr.Logger) (bool, error) {
+	allow, reason, err := r.deletionGate(ctx)
+	if err != nil {
+		log.Error(err, "Deletion gate failed")
+		return true, err
+	}
+	if allow {
+		if conditions := r.conditions(ctx.Object); conditions != nil {
+			RemoveStatusCondition(conditions, DeletionConditionType)
+		}
+		return false, nil
+	}
+
+	log.Info("Deletion blocked by gate", "reason", reason)
+	r.recorder.Event(ctx.Object, corev1.EventTypeWarning, "DeletionBlocked", reason)
+	ctx.Conditions.SetFalse(DeletionConditionType, DeletionBlockedReason, reason)
+	ctx.Conditions.Flush()
+
+	return true, nil
+}
+
+func (r *Reconciler) validate(ctx *Context, log logr.Logger) bool {
+	if err := r.validator(ctx.Object); err != nil {
+		log.Info("Skipping components, object is invalid", "reason", err.Error())
+		r.recorder.Event(ctx.Object, corev1.EventTypeWarning, "ValidationFailed", err.Error())
+		ctx.Conditions.SetFalse(ValidConditionType, "ValidationFailed", err.Error())
+		ctx.Conditions.Flush()
+
+		return false
+	}
+
+	ctx.Conditions.SetTrue(ValidConditionType, "ValidationSucceeded", "Object passed validation")
+	ctx.Conditions.Flush()
+
+	return true
+}
+
+// shouldSkip returns the metric label and the human-readable reason for skipping the reconcile of obj. The label is
+// empty when the reconcile should proceed.
+func (r *Reconciler) shouldSkip(ctx context.Context, obj client.Object) (skipReason, string, error) {
+	if skip, ok := obj.GetAnnotations()[SkipReconcileAnnotation]; ok && skip == "true" {
+		return skipReasonAnnotation, "due to annotation", nil
+	}
+	if r.pausedField != nil && r.pausedField(obj) {
+		return skipReasonPausedField, "due to paused field", nil
+	}
+	if r.skipPredicate != nil && r.skipPredicate(obj) {
+		return skipReasonPredicate, "due to skip predicate", nil
+	}
+
+	if r.namespacePauseKey != "" && obj.GetNamespace() != "" {
+		ns := &corev1.Namespace{}
+		if err := r.client.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, ns); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", "", nil
+			}
+			return "", "", fmt.Errorf("cannot get namespace %s: %w", obj.GetNamespace(), err)
+		}
+		if pause, ok := ns.GetAnnotations()[r.namespacePauseKey]; ok && pause == "true" {
+			return skipReasonNamespaceAnnotation, "due to namespace annotation", nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// pause records that reconciliation of obj is paused. The event is only recorded when the object becomes paused or the
+// reason changes, not on every skipped reconcile.
+func (r *Reconciler) pause(ctx context.Context, key client.ObjectKey, obj, cleanObj client.Object, reason string) error {
+	message := "Reconciliation is paused " + reason
+
+	conditions := r.conditions(obj)
+	if r.statusDisabled {
+		conditions = nil
+	}
+	prev, _ := r.pausedObjs.Load(key)
+	r.pausedObjs.Store(key, message)
+	if conditions != nil {
+		if cond := FindStatusCondition(*conditions, ReconciliationConditionType); cond != nil &&
+			cond.Reason == ReconciliationPausedReason {
+			prev = cond.Message
+		}
+	}
+	if prev != message {
+		r.recorder.Event(obj, corev1.EventTypeNormal, ReconciliationPausedReason, message)
+	}
+
+	if conditions == nil {
+		return nil
+	}
+	SetStatusCondition(conditions, metav1.Condition{
+		Type:               ReconciliationConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReconciliationPausedReason,
+		Message:            message,
+		ObservedGeneration: obj.GetGeneration(),
+	})
+
+	if err := r.client.Status().Patch(ctx, obj, client.MergeFrom(cleanObj)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error patching status: %w", err)
+	}
+
+	return nil
+}
+
+// hasStatusField reports whether a typed object declares a Status field. Unstructured objects are assumed to have one.
+func hasStatusField(obj client.Object) bool {
+	if _, ok := obj.(runtime.Unstructured); ok {
+		return true
+	}
+
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	_, ok := t.FieldByName("Status")
+	return ok
+}
+
+// withoutExcludedKeys returns current with the values of the excluded keys reverted to those in clean.
+func withoutExcludedKeys(current, clean map[string]string, excluded []string) map[string]string {
+	if len(excluded) == 0 {
+		return current
+	}
+
+	result := make(map[string]string, len(current))
+	for k, v := range current {
+		result[k] = v
+	}
+	for _, k := range excluded {
+		if v, ok := clean[k]; ok {
+			result[k] = v
+		} else {
+			delete(result, k)
+		}
+	}
+
+	return result
+}
+
+// mergeResults combines two reconcile results, keeping any requeue and the shortest non-zero RequeueAfter.
+func mergeResults(a, b ctrl.Result) ctrl.Result {
+	if b.Requeue {
+		a.Requeue = true
+	}
+	if b.RequeueAfter != 0 && (a.RequeueAfter == 0 || a.RequeueAfter > b.RequeueAfter) {
+		a.RequeueAfter = b.RequeueAfter
+	}
+
+	return a
+}
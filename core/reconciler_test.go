@@ -0,0 +1,1326 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var testGroupVersion = schema.GroupVersion{Group: "test.dominodatalab.com", Version: "v1"}
+
+// testObject is a minimal api type with a status subresource and conditions.
+type testObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   testObjectSpec   `json:"spec,omitempty"`
+	Status testObjectStatus `json:"status,omitempty"`
+}
+
+type testObjectSpec struct {
+	Replicas int32 `json:"replicas,omitempty"`
+	Paused   bool  `json:"paused,omitempty"`
+}
+
+type testObjectStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Reconcile  *ReconcileStatus   `json:"reconcile,omitempty"`
+}
+
+func (o *testObject) GetConditions() *[]metav1.Condition {
+	return &o.Status.Conditions
+}
+
+func (o *testObject) GetReconcileStatus() *ReconcileStatus {
+	return o.Status.Reconcile
+}
+
+func (o *testObject) DeepCopyObject() runtime.Object {
+	c := *o
+	o.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	if o.Status.Conditions != nil {
+		c.Status.Conditions = make([]metav1.Condition, len(o.Status.Conditions))
+		for i := range o.Status.Conditions {
+			o.Status.Conditions[i].DeepCopyInto(&c.Status.Conditions[i])
+		}
+	}
+	c.Status.Reconcile = o.Status.Reconcile.DeepCopy()
+	return &c
+}
+
+type testObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []testObject `json:"items"`
+}
+
+func (l *testObjectList) DeepCopyObject() runtime.Object {
+	c := *l
+	l.ListMeta.DeepCopyInto(&c.ListMeta)
+	if l.Items != nil {
+		c.Items = make([]testObject, len(l.Items))
+		for i := range l.Items {
+			c.Items[i] = *l.Items[i].DeepCopyObject().(*testObject)
+		}
+	}
+	return &c
+}
+
+func newTestScheme(t testing.TB) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(testGroupVersion, &testObject{}, &testObjectList{}, &bareObject{}, &bareObjectList{},
+		&healthObject{}, &healthObjectList{})
+	metav1.AddToGroupVersion(scheme, testGroupVersion)
+
+	return scheme
+}
+
+// componentFunc adapts a func to the Component interface.
+type componentFunc func(*Context) (ctrl.Result, error)
+
+func (f componentFunc) Reconcile(ctx *Context) (ctrl.Result, error) {
+	return f(ctx)
+}
+
+// finalizerFunc is a component whose finalizer is done once fn returns true.
+type finalizerFunc struct {
+	componentFunc
+	finalize func(*Context) (ctrl.Result, bool, error)
+}
+
+func (f finalizerFunc) Finalize(ctx *Context) (ctrl.Result, bool, error) {
+	return f.finalize(ctx)
+}
+
+// testReconciler builds reconcilers for testObject against a fake client. The manager is never started, so no api
+// server is contacted.
+type testReconciler struct {
+	t      testing.TB
+	client client.Client
+	mgr    ctrl.Manager
+	events *record.FakeRecorder
+}
+
+func newTestReconciler(t testing.TB, objs ...client.Object) *testReconciler {
+	t.Helper()
+	return newTestReconcilerWithFuncs(t, interceptor.Funcs{}, objs...)
+}
+
+// newTestReconcilerWithFuncs is like newTestReconciler but routes client calls through funcs, e.g. to inject errors.
+func newTestReconcilerWithFuncs(t testing.TB, funcs interceptor.Funcs, objs ...client.Object) *testReconciler {
+	t.Helper()
+
+	scheme := newTestScheme(t)
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "http://127.0.0.1:1"}, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		t.Fatalf("cannot create manager: %v", err)
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&testObject{}, &healthObject{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+
+	return &testReconciler{t: t, client: cl, mgr: mgr, events: record.NewFakeRecorder(100)}
+}
+
+// build returns a reconciler for testObject using the fake client. configure registers components and options.
+func (tr *testReconciler) build(name string, configure func(*Reconciler)) *Reconciler {
+	tr.t.Helper()
+
+	r := NewReconciler(tr.mgr).For(&testObject{}).Named(name)
+	r.client = tr.client
+	configure(r)
+	if _, err := r.Build(); err != nil {
+		tr.t.Fatalf("cannot build reconciler: %v", err)
+	}
+	r.recorder = newSafeRecorder(tr.events, tr.mgr.GetScheme(), r.log)
+
+	return r
+}
+
+// recordedEvents returns the events recorded since the last call, formatted as "<type> <reason> <message>".
+func (tr *testReconciler) recordedEvents() []string {
+	var events []string
+	for {
+		select {
+		case e := <-tr.events.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+// captureLogs returns a logger that appends every line, including those logged at V(1), to the returned slice.
+func captureLogs() (logr.Logger, *[]string) {
+	var lines []string
+	log := funcr.New(func(prefix, args string) {
+		lines = append(lines, strings.TrimSpace(prefix+" "+args))
+	}, funcr.Options{Verbosity: 1})
+	return log, &lines
+}
+
+// reconcile runs a single reconcile of the object identified by key.
+func (tr *testReconciler) reconcile(r *Reconciler, key client.ObjectKey) (ctrl.Result, error) {
+	return r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+}
+
+// get returns the stored state of the object identified by key.
+func (tr *testReconciler) get(key client.ObjectKey) *testObject {
+	tr.t.Helper()
+
+	obj := &testObject{}
+	if err := tr.client.Get(context.Background(), key, obj); err != nil {
+		tr.t.Fatalf("cannot get %s: %v", key, err)
+	}
+
+	return obj
+}
+
+func newTestObject(name string) *testObject {
+	return &testObject{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+func TestReconcileKeepsConcurrentFinalizers(t *testing.T) {
+	obj := newTestObject("finalizers")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("finalizers", func(r *Reconciler) {
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(ctx *Context) (ctrl.Result, error) {
+				// another controller adds its finalizer after the reconcile object was fetched
+				current := tr.get(key)
+				current.Finalizers = append(current.Finalizers, "example.com/other")
+				if err := tr.client.Update(ctx, current); err != nil {
+					return ctrl.Result{}, err
+				}
+
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	stored := tr.get(key)
+	for _, f := range []string{"example.com/other", "finalizers.test.dominodatalab.com/cleanup"} {
+		if !controllerutil.ContainsFinalizer(stored, f) {
+			t.Errorf("finalizer %s missing, got %v", f, stored.Finalizers)
+		}
+	}
+	if FindStatusCondition(stored.Status.Conditions, "Ready") == nil {
+		t.Errorf("Ready condition was not persisted, got %+v", stored.Status.Conditions)
+	}
+}
+
+func TestReconcileRemovesFinalizerWhenDone(t *testing.T) {
+	obj := newTestObject("finalize")
+	obj.Finalizers = []string{"finalize.test.dominodatalab.com/cleanup", "example.com/other"}
+	obj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	finalized := false
+	r := tr.build("finalize", func(r *Reconciler) {
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) {
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				finalized = true
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if !finalized {
+		t.Errorf("finalizer was not run")
+	}
+	if stored := tr.get(key); !reflect.DeepEqual(stored.Finalizers, []string{"example.com/other"}) {
+		t.Errorf("finalizers = %v, want only the finalizer of the other controller", stored.Finalizers)
+	}
+}
+
+func TestReconcilePersistsMetadataWhenStatusPatchFails(t *testing.T) {
+	obj := newTestObject("status-fails")
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+			return apierrors.NewInvalid(schema.GroupKind{Group: testGroupVersion.Group, Kind: "testObject"}, "status-fails", nil)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("status-fails", func(r *Reconciler) {
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(ctx *Context) (ctrl.Result, error) {
+				ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+				ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+
+	if _, err := tr.reconcile(r, key); err == nil || !strings.Contains(err.Error(), "error patching status") {
+		t.Fatalf("Reconcile() error = %v, want status patch error", err)
+	}
+
+	stored := tr.get(key)
+	if !controllerutil.ContainsFinalizer(stored, "status-fails.test.dominodatalab.com/cleanup") {
+		t.Errorf("finalizer was not persisted, got %v", stored.Finalizers)
+	}
+	if stored.Labels["example.com/labeled"] != "true" {
+		t.Errorf("labels = %v, want the label set by the component", stored.Labels)
+	}
+}
+
+// metaOnlyClone copies only the metadata of a testObject.
+func metaOnlyClone(obj client.Object) client.Object {
+	c := &testObject{TypeMeta: obj.(*testObject).TypeMeta}
+	obj.(*testObject).ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return c
+}
+
+func TestBuildRejectsAliasingMetaCloner(t *testing.T) {
+	tr := newTestReconciler(t)
+
+	r := NewReconciler(tr.mgr).For(&testObject{}).Named("aliasing")
+	r.WithMetaCloner(func(obj client.Object) client.Object { return obj })
+	if _, err := r.Build(); err == nil {
+		t.Errorf("Build() accepted a meta cloner returning its argument")
+	}
+}
+
+func TestReconcileWithMetaCloner(t *testing.T) {
+	obj := newTestObject("cloner")
+	obj.Spec.Replicas = 3
+
+	var clones []client.Object
+	var patched client.Object
+	var body string
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			patched, body = obj, string(data)
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("cloner", func(r *Reconciler) {
+		r.WithMetaCloner(func(obj client.Object) client.Object {
+			c := metaOnlyClone(obj)
+			clones = append(clones, c)
+			return c
+		})
+		r.Component("label", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+			ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+			return ctrl.Result{}, nil
+		}))
+	})
+	// Build probes the cloner once
+	clones = nil
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	found := false
+	for _, c := range clones {
+		found = found || c == patched
+	}
+	if !found {
+		t.Errorf("metadata patch was not built from an object returned by the meta cloner")
+	}
+	if want := `{"metadata":{"labels":{"example.com/labeled":"true"}}}`; body != want {
+		t.Errorf("metadata patch = %s, want %s", body, want)
+	}
+
+	stored := tr.get(key)
+	if stored.Labels["example.com/labeled"] != "true" {
+		t.Errorf("labels = %v, want the label set by the component", stored.Labels)
+	}
+	if stored.Spec.Replicas != 3 {
+		t.Errorf("spec.replicas = %d, want 3", stored.Spec.Replicas)
+	}
+	if FindStatusCondition(stored.Status.Conditions, "Ready") == nil {
+		t.Errorf("Ready condition was not persisted, got %+v", stored.Status.Conditions)
+	}
+}
+
+// BenchmarkCloneMeta measures cloning the api type prototype, which is what reconciles clone to build the metadata
+// and finalizer patches.
+func BenchmarkCloneMeta(b *testing.B) {
+	for _, bc := range []struct {
+		name   string
+		cloner MetaCloner
+	}{
+		{name: "DeepCopyObject"},
+		{name: "MetaOnly", cloner: metaOnlyClone},
+	} {
+		r := &Reconciler{apiType: &testObject{}, metaCloner: bc.cloner}
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r.cloneMeta(r.apiType)
+			}
+		})
+	}
+}
+
+func TestReconcileDrainsPatchesOnShutdown(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		timeout time.Duration
+		persist bool
+	}{
+		{name: "without drain timeout"},
+		{name: "with drain timeout", timeout: time.Second, persist: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("drain")
+			// like a real client, refuse requests once their context is done
+			tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+				SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+				},
+			}, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			started := make(chan struct{})
+			r := tr.build("drain", func(r *Reconciler) {
+				r.WithShutdownDrainTimeout(tc.timeout)
+				r.Component("block", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+					ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+					close(started)
+					<-ctx.Done()
+					return ctrl.Result{}, ctx.Err()
+				}))
+			})
+
+			rootCtx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				_, err := r.Reconcile(rootCtx, ctrl.Request{NamespacedName: key})
+				done <- err
+			}()
+
+			<-started
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Reconcile() did not return after the context was cancelled")
+			}
+
+			stored := tr.get(key)
+			if got := stored.Labels["example.com/labeled"] == "true"; got != tc.persist {
+				t.Errorf("labels = %v, want label persisted = %t", stored.Labels, tc.persist)
+			}
+			if got := FindStatusCondition(stored.Status.Conditions, "Ready") != nil; got != tc.persist {
+				t.Errorf("conditions = %+v, want Ready persisted = %t", stored.Status.Conditions, tc.persist)
+			}
+		})
+	}
+}
+
+func TestReconcileRecordsPauseTransitions(t *testing.T) {
+	obj := newTestObject("paused")
+	obj.Annotations = map[string]string{SkipReconcileAnnotation: "true"}
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("paused", func(r *Reconciler) {
+		r.Component("noop", componentFunc(func(*Context) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		}))
+	})
+	setPaused := func(paused bool) {
+		t.Helper()
+		stored := tr.get(key)
+		stored.Annotations = nil
+		if paused {
+			stored.Annotations = map[string]string{SkipReconcileAnnotation: "true"}
+		}
+		if err := tr.client.Update(context.Background(), stored); err != nil {
+			t.Fatalf("cannot update object: %v", err)
+		}
+	}
+	pausedEvents := func() int {
+		n := 0
+		for _, e := range tr.recordedEvents() {
+			if strings.HasPrefix(e, "Normal "+ReconciliationPausedReason) {
+				n++
+			}
+		}
+		return n
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if n := pausedEvents(); n != 1 {
+		t.Errorf("recorded %d Paused events for a paused object, want 1", n)
+	}
+	cond := FindStatusCondition(tr.get(key).Status.Conditions, ReconciliationConditionType)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReconciliationPausedReason {
+		t.Errorf("Reconciliation condition = %+v, want False/%s", cond, ReconciliationPausedReason)
+	}
+
+	setPaused(false)
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if cond := FindStatusCondition(tr.get(key).Status.Conditions, ReconciliationConditionType); cond != nil {
+		t.Errorf("Reconciliation condition not removed on resume: %+v", cond)
+	}
+
+	setPaused(true)
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if n := pausedEvents(); n != 1 {
+		t.Errorf("recorded %d Paused events after pausing again, want 1", n)
+	}
+}
+
+func TestReconcileUsesPreFetchedObject(t *testing.T) {
+	obj := newTestObject("pre-fetched")
+	gets := 0
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*testObject); ok {
+				gets++
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var replicas int32
+	r := tr.build("pre-fetched", func(r *Reconciler) {
+		r.WithPreFetchedObject()
+		r.Component("record", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			replicas = ctx.Object.(*testObject).Spec.Replicas
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	fetched := tr.get(key)
+	fetched.Spec.Replicas = 2
+	gets = 0
+	parent := &Context{Context: context.Background(), Data: ContextData{PreFetchedObjectContextDataKey: fetched}}
+	if _, err := r.Reconcile(parent, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if gets != 0 {
+		t.Errorf("reconcile with a pre-fetched object performed %d Gets, want none", gets)
+	}
+	if replicas != 2 {
+		t.Errorf("component saw replicas = %d, want those of the pre-fetched object", replicas)
+	}
+
+	parent.Data = ContextData{}
+	if _, err := r.Reconcile(parent, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if gets != 1 {
+		t.Errorf("reconcile without a pre-fetched object performed %d Gets, want 1", gets)
+	}
+}
+
+func TestBuildValidatesComponentNames(t *testing.T) {
+	noop := componentFunc(func(*Context) (ctrl.Result, error) {
+		return ctrl.Result{}, nil
+	})
+
+	cases := []struct {
+		name   string
+		policy *regexp.Regexp
+		valid  bool
+	}{
+		{name: "web", valid: true},
+		{name: "web-1", valid: true},
+		{name: "1", valid: true},
+		{name: ""},
+		{name: "Web"},
+		{name: "web.app"},
+		{name: "web_app"},
+		{name: "-web"},
+		{name: "web-"},
+		{name: "Web_App.v1", policy: regexp.MustCompile(`^[A-Za-z0-9._-]+$`), valid: true},
+	}
+
+	tr := newTestReconciler(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReconciler(tr.mgr).For(&testObject{}).Named("names").Component(tc.name, noop)
+			if tc.policy != nil {
+				r.WithComponentNamePolicy(tc.policy)
+			}
+
+			_, err := r.Build()
+			if tc.valid && err != nil {
+				t.Errorf("Build() rejected component name %q: %v", tc.name, err)
+			}
+			if !tc.valid && (err == nil || !strings.Contains(err.Error(), "does not match naming policy")) {
+				t.Errorf("Build() error = %v, want component name %q rejected", err, tc.name)
+			}
+		})
+	}
+}
+
+func TestComponentOrder(t *testing.T) {
+	obj := newTestObject("ordered")
+	tr := newTestReconciler(t, obj)
+
+	var invoked []string
+	record := func(name string) Component {
+		return componentFunc(func(*Context) (ctrl.Result, error) {
+			invoked = append(invoked, name)
+			return ctrl.Result{}, nil
+		})
+	}
+
+	want := []string{"config", "workload", "service"}
+	r := tr.build("ordered", func(r *Reconciler) {
+		for _, name := range want {
+			r.Component(name, record(name))
+		}
+	})
+
+	if got := r.ComponentOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComponentOrder() = %v, want %v", got, want)
+	}
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !reflect.DeepEqual(invoked, r.ComponentOrder()) {
+		t.Errorf("components reconciled in order %v, want %v", invoked, r.ComponentOrder())
+	}
+}
+
+func TestDeletionGate(t *testing.T) {
+	cases := []struct {
+		name    string
+		allow   bool
+		wantRan bool
+	}{
+		{name: "blocked", allow: false, wantRan: false},
+		{name: "allowed", allow: true, wantRan: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("gated")
+			obj.Finalizers = []string{"gated.test.dominodatalab.com/cleanup", "example.com/other"}
+			obj.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			ran := false
+			r := tr.build("gated", func(r *Reconciler) {
+				r.WithDeletionGate(func(*Context) (bool, string, error) {
+					return tc.allow, "still referenced by other objects", nil
+				})
+				r.Component("cleanup", finalizerFunc{
+					componentFunc: func(*Context) (ctrl.Result, error) {
+						return ctrl.Result{}, nil
+					},
+					finalize: func(*Context) (ctrl.Result, bool, error) {
+						ran = true
+						return ctrl.Result{}, true, nil
+					},
+				})
+			})
+
+			res, err := tr.reconcile(r, key)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if ran != tc.wantRan {
+				t.Errorf("finalizer ran = %t, want %t", ran, tc.wantRan)
+			}
+
+			stored := tr.get(key)
+			if !tc.allow {
+				if !res.Requeue {
+					t.Errorf("Reconcile() = %+v, want a requeue while deletion is blocked", res)
+				}
+				if !controllerutil.ContainsFinalizer(stored, "gated.test.dominodatalab.com/cleanup") {
+					t.Errorf("finalizers = %v, want the component finalizer kept", stored.Finalizers)
+				}
+				assertCondition(t, stored, DeletionConditionType, metav1.ConditionFalse)
+				want := "Warning DeletionBlocked still referenced by other objects"
+				if events := tr.recordedEvents(); len(events) != 1 || events[0] != want {
+					t.Errorf("events = %q, want %q", events, want)
+				}
+				return
+			}
+
+			if controllerutil.ContainsFinalizer(stored, "gated.test.dominodatalab.com/cleanup") {
+				t.Errorf("finalizers = %v, want the component finalizer removed", stored.Finalizers)
+			}
+			if FindStatusCondition(stored.Status.Conditions, DeletionConditionType) != nil {
+				t.Errorf("conditions = %+v, want no deletion condition", stored.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestWithSchemeBuilder(t *testing.T) {
+	newManager := func() ctrl.Manager {
+		mgr, err := ctrl.NewManager(&rest.Config{Host: "http://127.0.0.1:1"}, ctrl.Options{
+			Scheme:  runtime.NewScheme(),
+			Metrics: metricsserver.Options{BindAddress: "0"},
+		})
+		if err != nil {
+			t.Fatalf("cannot create manager: %v", err)
+		}
+		return mgr
+	}
+	sb := (&scheme.Builder{GroupVersion: testGroupVersion}).Register(&testObject{}, &testObjectList{})
+
+	mgr := newManager()
+	if _, err := NewReconciler(mgr).For(&testObject{}).Named("unregistered").Build(); err == nil {
+		t.Fatal("Build() succeeded for a type missing from the scheme")
+	}
+
+	mgr = newManager()
+	r := NewReconciler(mgr).For(&testObject{}).Named("registered").WithSchemeBuilder(sb.AddToScheme)
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if _, _, err := mgr.GetScheme().ObjectKinds(&testObject{}); err != nil {
+		t.Errorf("type not registered with the manager's scheme: %v", err)
+	}
+
+	failing := func(*runtime.Scheme) error { return errors.New("conflicting registration") }
+	_, err := NewReconciler(newManager()).For(&testObject{}).Named("failing").WithSchemeBuilder(failing).Build()
+	if err == nil || !strings.Contains(err.Error(), "conflicting registration") {
+		t.Errorf("Build() error = %v, want the scheme builder error", err)
+	}
+}
+
+func TestReconcileIdentityMutation(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(client.Object)
+	}{
+		{name: "name", mutate: func(obj client.Object) { obj.SetName("other") }},
+		{name: "namespace", mutate: func(obj client.Object) { obj.SetNamespace("other") }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("identity")
+			patches := 0
+			tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patches++
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+				SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					patches++
+					return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+				},
+			}, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			ranAfter := false
+			r := tr.build("identity", func(r *Reconciler) {
+				r.Component("rename", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Object.SetLabels(map[string]string{"example.com/renamed": "true"})
+					ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+					tc.mutate(ctx.Object)
+					return ctrl.Result{}, nil
+				}))
+				r.Component("after", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ranAfter = client.ObjectKeyFromObject(ctx.Object) == key
+					return ctrl.Result{}, nil
+				}))
+			})
+
+			_, err := tr.reconcile(r, key)
+			if err == nil || !strings.Contains(err.Error(), "component rename changed object identity") {
+				t.Fatalf("Reconcile() error = %v, want the identity change reported", err)
+			}
+			if !ranAfter {
+				t.Error("later component did not see the restored identity")
+			}
+			if patches != 0 {
+				t.Errorf("sent %d patches, want none", patches)
+			}
+			stored := tr.get(key)
+			if len(stored.Labels) != 0 || len(stored.Status.Conditions) != 0 {
+				t.Errorf("stored object = %+v, want it unchanged", stored.ObjectMeta)
+			}
+		})
+	}
+}
+
+func TestReconcilePausedField(t *testing.T) {
+	obj := newTestObject("paused-field")
+	obj.Spec.Paused = true
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	ran := 0
+	r := tr.build("paused-field", func(r *Reconciler) {
+		r.WithPausedField(func(obj client.Object) bool {
+			return obj.(*testObject).Spec.Paused
+		})
+		r.Component("count", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran++
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if ran != 0 {
+		t.Errorf("component ran %d times while paused, want 0", ran)
+	}
+	cond := FindStatusCondition(tr.get(key).Status.Conditions, ReconciliationConditionType)
+	if cond == nil || cond.Reason != ReconciliationPausedReason || cond.Message != "Reconciliation is paused due to paused field" {
+		t.Errorf("Reconciliation condition = %+v, want paused due to the field", cond)
+	}
+
+	stored := tr.get(key)
+	stored.Spec.Paused = false
+	if err := tr.client.Update(context.Background(), stored); err != nil {
+		t.Fatalf("cannot update object: %v", err)
+	}
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if ran != 1 {
+		t.Errorf("component ran %d times after resuming, want 1", ran)
+	}
+	if cond := FindStatusCondition(tr.get(key).Status.Conditions, ReconciliationConditionType); cond != nil {
+		t.Errorf("Reconciliation condition not removed on resume: %+v", cond)
+	}
+}
+
+func TestReconcileNamespacePauseAnnotation(t *testing.T) {
+	const key = "example.com/maintenance"
+
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		wantRan     bool
+	}{
+		{name: "paused namespace", annotations: map[string]string{key: "true"}},
+		{name: "unpaused namespace", annotations: map[string]string{key: "false"}, wantRan: true},
+		{name: "unannotated namespace", wantRan: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("namespaced")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: obj.Namespace, Annotations: tc.annotations}}
+
+			tr := newTestReconciler(t)
+			scheme := newTestScheme(t)
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("cannot register types: %v", err)
+			}
+			tr.client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(obj, ns).
+				WithStatusSubresource(&testObject{}).
+				Build()
+
+			ran := false
+			r := tr.build("namespace-pause", func(r *Reconciler) {
+				r.WithNamespacePauseAnnotation(key)
+				r.Component("run", componentFunc(func(*Context) (ctrl.Result, error) {
+					ran = true
+					return ctrl.Result{}, nil
+				}))
+			})
+
+			if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if ran != tc.wantRan {
+				t.Errorf("component ran = %t, want %t", ran, tc.wantRan)
+			}
+		})
+	}
+}
+
+func TestGetComponent(t *testing.T) {
+	tr := newTestReconciler(t)
+	comp := finalizerFunc{componentFunc: func(*Context) (ctrl.Result, error) {
+		return ctrl.Result{}, nil
+	}}
+	r := tr.build("lookup", func(r *Reconciler) {
+		r.Component("cleanup", comp)
+	})
+
+	got, ok := r.GetComponent("cleanup")
+	if !ok {
+		t.Fatal("GetComponent() did not find a registered component")
+	}
+	if _, isFinalizer := got.(FinalizerComponent); !isFinalizer {
+		t.Errorf("GetComponent() = %T, want the registered component", got)
+	}
+
+	if got, ok = r.GetComponent("missing"); ok || got != nil {
+		t.Errorf("GetComponent(missing) = %v, %t, want nothing", got, ok)
+	}
+}
+
+func TestReconcileObjectDefaulting(t *testing.T) {
+	cases := []struct {
+		name       string
+		defaulting bool
+		want       int32
+	}{
+		{name: "disabled", want: 0},
+		{name: "enabled", defaulting: true, want: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("defaulted")
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+			tr.mgr.GetScheme().AddTypeDefaultingFunc(&testObject{}, func(o interface{}) {
+				if spec := &o.(*testObject).Spec; spec.Replicas == 0 {
+					spec.Replicas = 1
+				}
+			})
+
+			var seen int32 = -1
+			r := tr.build("defaulted", func(r *Reconciler) {
+				if tc.defaulting {
+					r.WithObjectDefaulting()
+				}
+				r.Component("inspect", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					seen = ctx.Object.(*testObject).Spec.Replicas
+					return ctrl.Result{}, nil
+				}))
+			})
+
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if seen != tc.want {
+				t.Errorf("components saw replicas = %d, want %d", seen, tc.want)
+			}
+			if got := tr.get(key).Spec.Replicas; got != tc.want {
+				t.Errorf("stored replicas = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMetadataPatchExclusions(t *testing.T) {
+	obj := newTestObject("exclusions")
+	obj.Labels = map[string]string{"example.com/owned-elsewhere": "original"}
+	obj.Annotations = map[string]string{"example.com/managed-elsewhere": "original"}
+
+	var patches []string
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			patches = append(patches, string(data))
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("exclusions", func(r *Reconciler) {
+		r.WithMetadataPatchExclusions([]string{"example.com/managed-elsewhere"}, []string{"example.com/owned-elsewhere"})
+		r.Component("labels", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Object.SetLabels(map[string]string{
+				"example.com/owned-elsewhere": "changed",
+				"example.com/labeled":         "true",
+			})
+			ctx.Object.SetAnnotations(map[string]string{"example.com/annotated": "true"})
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if len(patches) == 0 {
+		t.Fatal("metadata was not patched")
+	}
+	for _, p := range patches {
+		if strings.Contains(p, "owned-elsewhere") || strings.Contains(p, "managed-elsewhere") {
+			t.Errorf("patch %s contains an excluded key", p)
+		}
+	}
+
+	stored := tr.get(key)
+	wantLabels := map[string]string{"example.com/owned-elsewhere": "original", "example.com/labeled": "true"}
+	if !reflect.DeepEqual(stored.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", stored.Labels, wantLabels)
+	}
+	wantAnnotations := map[string]string{"example.com/managed-elsewhere": "original", "example.com/annotated": "true"}
+	if !reflect.DeepEqual(stored.Annotations, wantAnnotations) {
+		t.Errorf("annotations = %v, want %v", stored.Annotations, wantAnnotations)
+	}
+}
+
+func TestSoftDeleteLabel(t *testing.T) {
+	obj := newTestObject("soft-delete")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var reconciled, finalized int
+	r := tr.build("soft-delete", func(r *Reconciler) {
+		r.WithSoftDeleteLabel("example.com/deleted")
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(*Context) (ctrl.Result, error) {
+				reconciled++
+				return ctrl.Result{}, nil
+			},
+			finalize: func(*Context) (ctrl.Result, bool, error) {
+				finalized++
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+	finalizer := "soft-delete.test.dominodatalab.com/cleanup"
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	stored := tr.get(key)
+	if reconciled != 1 || !controllerutil.ContainsFinalizer(stored, finalizer) {
+		t.Fatalf("component ran %d times, finalizers = %v, want one run and %s", reconciled, stored.Finalizers, finalizer)
+	}
+
+	stored.Labels = map[string]string{"example.com/deleted": "true"}
+	if err := tr.client.Update(context.Background(), stored); err != nil {
+		t.Fatalf("cannot label object: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	if finalized != 1 {
+		t.Errorf("finalizer ran %d times, want 1", finalized)
+	}
+	if reconciled != 1 {
+		t.Errorf("component ran %d times after soft deletion, want it skipped", reconciled-1)
+	}
+	stored = tr.get(key)
+	if len(stored.Finalizers) != 0 {
+		t.Errorf("finalizers = %v, want them removed", stored.Finalizers)
+	}
+	if !stored.DeletionTimestamp.IsZero() {
+		t.Error("soft deleted object has a deletion timestamp")
+	}
+}
+
+func TestReconcileFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		labels   map[string]string
+		filtered bool
+	}{
+		{name: "accepted", labels: map[string]string{"example.com/managed": "true"}},
+		{name: "filtered", filtered: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("filter")
+			obj.Labels = tc.labels
+
+			writes := 0
+			tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					writes++
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+				SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					writes++
+					return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+				},
+			}, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			ran := false
+			r := tr.build("filter", func(r *Reconciler) {
+				r.WithReconcileFilter(func(obj client.Object) bool {
+					return obj.GetLabels()["example.com/managed"] == "true"
+				})
+				r.Component("cleanup", finalizerFunc{
+					componentFunc: func(ctx *Context) (ctrl.Result, error) {
+						ran = true
+						ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+						return ctrl.Result{}, nil
+					},
+					finalize: func(*Context) (ctrl.Result, bool, error) {
+						return ctrl.Result{}, true, nil
+					},
+				})
+			})
+
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if ran == tc.filtered {
+				t.Errorf("component ran = %t, want %t", ran, !tc.filtered)
+			}
+			if tc.filtered && writes != 0 {
+				t.Errorf("filtered object was patched %d times", writes)
+			}
+			stored := tr.get(key)
+			if tc.filtered && (len(stored.Finalizers) != 0 || len(stored.Status.Conditions) != 0) {
+				t.Errorf("filtered object changed: finalizers = %v, conditions = %+v", stored.Finalizers, stored.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestSuspendField(t *testing.T) {
+	for _, suspended := range []bool{false, true} {
+		t.Run(fmt.Sprintf("suspended=%t", suspended), func(t *testing.T) {
+			obj := newTestObject("suspend")
+			obj.Spec.Paused = suspended
+			tr := newTestReconciler(t, obj)
+			key := client.ObjectKeyFromObject(obj)
+
+			var reconciled, finalized int
+			r := tr.build("suspend", func(r *Reconciler) {
+				r.WithSuspendField(func(obj client.Object) bool {
+					return obj.(*testObject).Spec.Paused
+				})
+				r.Component("cleanup", finalizerFunc{
+					componentFunc: func(*Context) (ctrl.Result, error) {
+						reconciled++
+						return ctrl.Result{}, nil
+					},
+					finalize: func(*Context) (ctrl.Result, bool, error) {
+						finalized++
+						return ctrl.Result{}, true, nil
+					},
+				})
+			})
+
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if want := map[bool]int{false: 1, true: 0}[suspended]; reconciled != want {
+				t.Errorf("component ran %d times, want %d", reconciled, want)
+			}
+			stored := tr.get(key)
+			if !controllerutil.ContainsFinalizer(stored, "suspend.test.dominodatalab.com/cleanup") {
+				t.Fatal("finalizer not registered")
+			}
+			if cond := FindStatusCondition(stored.Status.Conditions, SuspendedConditionType); (cond != nil) != suspended {
+				t.Errorf("Suspended condition = %+v, want it set %t", cond, suspended)
+			}
+
+			if suspended {
+				stored.Spec.Paused = false
+				if err := tr.client.Update(context.Background(), stored); err != nil {
+					t.Fatalf("cannot resume object: %v", err)
+				}
+				if _, err := tr.reconcile(r, key); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+				if reconciled != 1 {
+					t.Errorf("component ran %d times after resuming, want 1", reconciled)
+				}
+				if cond := FindStatusCondition(tr.get(key).Status.Conditions, SuspendedConditionType); cond != nil {
+					t.Errorf("Suspended condition = %+v, want it cleared on resume", cond)
+				}
+			}
+
+			if err := tr.client.Delete(context.Background(), tr.get(key)); err != nil {
+				t.Fatalf("cannot delete object: %v", err)
+			}
+			if _, err := tr.reconcile(r, key); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if finalized != 1 {
+				t.Errorf("finalizer ran %d times, want 1", finalized)
+			}
+		})
+	}
+}
+
+// ownedComponentFunc is a component owning healthObjects.
+type ownedComponentFunc struct {
+	componentFunc
+}
+
+func (ownedComponentFunc) Kind() client.Object {
+	return &healthObject{}
+}
+
+func TestWatchedKinds(t *testing.T) {
+	tr := newTestReconciler(t)
+	noop := func(*Context) (ctrl.Result, error) { return ctrl.Result{}, nil }
+
+	r := NewReconciler(tr.mgr).For(&testObject{}).Named("watched")
+	r.client = tr.client
+	r.WithSchemeBuilder(corev1.AddToScheme)
+	r.Component("owned", ownedComponentFunc{noop})
+	r.Component("plain", componentFunc(noop))
+	r.WatchesOwned(&healthObject{})
+	r.WatchesContent(&corev1.Secret{}, func(context.Context, client.Object) []reconcile.Request { return nil })
+	if got := r.WatchedKinds(); len(got) != 0 {
+		t.Errorf("WatchedKinds() = %v before Build, want none", got)
+	}
+
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+
+	want := []schema.GroupVersionKind{
+		testGroupVersion.WithKind("testObject"),
+		testGroupVersion.WithKind("healthObject"),
+		corev1.SchemeGroupVersion.WithKind("Secret"),
+	}
+	if got := r.WatchedKinds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("WatchedKinds() = %v, want %v", got, want)
+	}
+}
+
+func TestObjectMutationGuard(t *testing.T) {
+	const (
+		droppedStatus = "Components changed the object status but status patching is disabled"
+		leakedStatus  = "Metadata patch includes status fields"
+	)
+
+	cases := []struct {
+		name      string
+		configure func(r *Reconciler, leak *bool)
+		want      string
+	}{
+		{name: "clean"},
+		{
+			name:      "status disabled",
+			configure: func(r *Reconciler, _ *bool) { r.WithoutStatus() },
+			want:      droppedStatus,
+		},
+		{
+			name: "status in metadata patch",
+			configure: func(r *Reconciler, leak *bool) {
+				// a misbehaving cloner copying status into the first clone made after the component ran
+				r.WithMetaCloner(func(client.Object) client.Object {
+					obj := &testObject{}
+					if *leak {
+						*leak = false
+						obj.Status.Conditions = []metav1.Condition{{Type: "Leaked", Status: metav1.ConditionTrue}}
+					}
+					return obj
+				})
+			},
+			want: leakedStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("guard")
+			tr := newTestReconciler(t, obj)
+
+			leak := false
+			r := tr.build("guard", func(r *Reconciler) {
+				r.WithObjectMutationGuard()
+				if tc.configure != nil {
+					tc.configure(r, &leak)
+				}
+				r.Component("status", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+					ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+					leak = true
+					return ctrl.Result{}, nil
+				}))
+			})
+			log, lines := captureLogs()
+			r.log = log
+
+			if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			for _, msg := range []string{droppedStatus, leakedStatus} {
+				found := false
+				for _, line := range *lines {
+					found = found || strings.Contains(line, msg)
+				}
+				if found != (msg == tc.want) {
+					t.Errorf("logged %q = %t, want %t", msg, found, msg == tc.want)
+				}
+			}
+		})
+	}
+}
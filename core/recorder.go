@@ -0,0 +1,63 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// safeRecorder drops events for objects whose type is not registered with the scheme instead of letting the
+// underlying recorder fail. Each unregistered type is logged once.
+type safeRecorder struct {
+	record.EventRecorder
+
+	scheme *runtime.Scheme
+	log    logr.Logger
+	logged sync.Map
+}
+
+func newSafeRecorder(recorder record.EventRecorder, scheme *runtime.Scheme, log logr.Logger) *safeRecorder {
+	return &safeRecorder{EventRecorder: recorder, scheme: scheme, log: log}
+}
+
+func (s *safeRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	s.guard(object, func() {
+		s.EventRecorder.Event(object, eventtype, reason, message)
+	})
+}
+
+func (s *safeRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	s.guard(object, func() {
+		s.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	})
+}
+
+func (s *safeRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	s.guard(object, func() {
+		s.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	})
+}
+
+func (s *safeRecorder) guard(object runtime.Object, emit func()) {
+	if _, _, err := s.scheme.ObjectKinds(object); err != nil {
+		s.logOnce(object, err)
+		return
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.logOnce(object, fmt.Errorf("recovered from panic: %v", rec))
+		}
+	}()
+	emit()
+}
+
+func (s *safeRecorder) logOnce(object runtime.Object, err error) {
+	if _, loaded := s.logged.LoadOrStore(reflect.TypeOf(object), struct{}{}); !loaded {
+		s.log.Error(err, "Cannot record events for object type", "type", fmt.Sprintf("%T", object))
+	}
+}
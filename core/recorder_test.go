@@ -0,0 +1,167 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// panickingRecorder fails the way a recorder does when it cannot build a reference to the object.
+type panickingRecorder struct {
+	record.EventRecorder
+}
+
+func (panickingRecorder) Event(runtime.Object, string, string, string) {
+	panic("cannot build object reference")
+}
+
+func TestSafeRecorderUnknownType(t *testing.T) {
+	log, lines := captureLogs()
+	events := record.NewFakeRecorder(10)
+	recorder := newSafeRecorder(events, runtime.NewScheme(), log)
+
+	obj := &corev1.ConfigMap{}
+	recorder.Event(obj, corev1.EventTypeNormal, "Reason", "first")
+	recorder.Eventf(obj, corev1.EventTypeNormal, "Reason", "second %d", 2)
+	recorder.AnnotatedEventf(obj, map[string]string{"a": "b"}, corev1.EventTypeNormal, "Reason", "third")
+
+	if len(events.Events) != 0 {
+		t.Errorf("recorded %d events for an unregistered type, want none", len(events.Events))
+	}
+	if len(*lines) != 1 || !strings.Contains((*lines)[0], `"type"="*v1.ConfigMap"`) {
+		t.Errorf("log = %q, want a single line naming the type", *lines)
+	}
+}
+
+func TestSafeRecorderKnownType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot register types: %v", err)
+	}
+	events := record.NewFakeRecorder(10)
+	recorder := newSafeRecorder(events, scheme, logr.Discard())
+
+	recorder.Event(&corev1.ConfigMap{}, corev1.EventTypeNormal, "Reason", "message")
+	if got := <-events.Events; got != "Normal Reason message" {
+		t.Errorf("event = %q, want it passed through", got)
+	}
+}
+
+func TestSafeRecorderRecoversPanic(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot register types: %v", err)
+	}
+	log, lines := captureLogs()
+	recorder := newSafeRecorder(panickingRecorder{}, scheme, log)
+
+	recorder.Event(&corev1.ConfigMap{}, corev1.EventTypeNormal, "Reason", "message")
+	if len(*lines) != 1 || !strings.Contains((*lines)[0], "recovered from panic") {
+		t.Errorf("log = %q, want the recovered panic logged", *lines)
+	}
+}
+
+// sourceRecordingManager hands out a FakeRecorder per event source so tests can tell which source an event used.
+type sourceRecordingManager struct {
+	ctrl.Manager
+	recorders map[string]*record.FakeRecorder
+}
+
+func newSourceRecordingManager(mgr ctrl.Manager) *sourceRecordingManager {
+	return &sourceRecordingManager{Manager: mgr, recorders: map[string]*record.FakeRecorder{}}
+}
+
+func (m *sourceRecordingManager) GetEventRecorderFor(name string) record.EventRecorder {
+	if _, ok := m.recorders[name]; !ok {
+		m.recorders[name] = record.NewFakeRecorder(10)
+	}
+	return m.recorders[name]
+}
+
+// events returns the events recorded under source.
+func (m *sourceRecordingManager) events(source string) []string {
+	var events []string
+	if rec, ok := m.recorders[source]; ok {
+		for len(rec.Events) > 0 {
+			events = append(events, <-rec.Events)
+		}
+	}
+	return events
+}
+
+func TestEventSource(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "default", want: "events-controller"},
+		{name: "custom", source: "shared-events", want: "shared-events"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("events")
+			tr := newTestReconciler(t, obj)
+			mgr := newSourceRecordingManager(tr.mgr)
+
+			r := NewReconciler(mgr).For(&testObject{}).Named("events")
+			r.client = tr.client
+			if tc.source != "" {
+				r.WithEventSource(tc.source)
+			}
+			r.Component("notify", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+				ctx.Recorder.Event(ctx.Object, corev1.EventTypeNormal, "Notified", "component ran")
+				return ctrl.Result{}, nil
+			}))
+			if _, err := r.Build(); err != nil {
+				t.Fatalf("cannot build reconciler: %v", err)
+			}
+
+			if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if events := mgr.events(tc.want); len(events) != 1 || events[0] != "Normal Notified component ran" {
+				t.Errorf("events recorded under %q = %q, want the component event", tc.want, events)
+			}
+		})
+	}
+}
+
+func TestComponentRecorders(t *testing.T) {
+	obj := newTestObject("recorders")
+	tr := newTestReconciler(t, obj)
+	mgr := newSourceRecordingManager(tr.mgr)
+
+	r := NewReconciler(mgr).For(&testObject{}).Named("recorders").WithComponentRecorders()
+	r.client = tr.client
+	for _, name := range []string{"database", "server"} {
+		name := name
+		r.Component(name, componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Recorder.Event(ctx.Object, corev1.EventTypeNormal, "Notified", name+" ran")
+			return ctrl.Result{}, nil
+		}))
+	}
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+
+	if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for _, name := range []string{"database", "server"} {
+		source := "recorders-" + name
+		if events := mgr.events(source); len(events) != 1 || events[0] != "Normal Notified "+name+" ran" {
+			t.Errorf("events recorded under %q = %q, want the %s event", source, events, name)
+		}
+	}
+	if events := mgr.events("recorders-controller"); len(events) != 0 {
+		t.Errorf("component events recorded under the controller source: %q", events)
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConditionRequeueBacksOffWhileFalse(t *testing.T) {
+	defer func(d time.Duration) { MaxConditionRequeueInterval = d }(MaxConditionRequeueInterval)
+	MaxConditionRequeueInterval = 30 * time.Second
+
+	obj := newTestObject("condition-requeue")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	status := metav1.ConditionFalse
+	reason := "Pending"
+	r := tr.build("condition-requeue", func(r *Reconciler) {
+		r.WithConditionRequeue(map[string]time.Duration{"Pending": 5 * time.Second, "Failed": 0})
+		r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Conditions.Set("Ready", status, reason, "")
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}))
+	})
+
+	steps := []struct {
+		status metav1.ConditionStatus
+		reason string
+		want   time.Duration
+	}{
+		{status: metav1.ConditionFalse, reason: "Pending", want: 5 * time.Second},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 10 * time.Second},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 20 * time.Second},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 30 * time.Second},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 30 * time.Second},
+		// the component result applies once the condition is True
+		{status: metav1.ConditionTrue, reason: "Pending", want: time.Minute},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 5 * time.Second},
+		// terminal reasons suppress requeues and reset the backoff
+		{status: metav1.ConditionFalse, reason: "Failed", want: 0},
+		{status: metav1.ConditionFalse, reason: "Pending", want: 5 * time.Second},
+	}
+
+	for i, step := range steps {
+		status, reason = step.status, step.reason
+		res, err := tr.reconcile(r, key)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if res.RequeueAfter != step.want {
+			t.Errorf("reconcile %d (%s/%s): RequeueAfter = %v, want %v", i+1, step.status, step.reason, res.RequeueAfter, step.want)
+		}
+	}
+}
+
+func TestSpecRequeueInterval(t *testing.T) {
+	const key = "example.com/poll-interval"
+	extract := func(obj client.Object) (time.Duration, bool) {
+		value, ok := obj.GetAnnotations()[key]
+		if !ok {
+			return 0, false
+		}
+		interval, err := time.ParseDuration(value)
+		return interval, err == nil
+	}
+
+	cases := []struct {
+		name      string
+		interval  string
+		requested time.Duration
+		want      time.Duration
+	}{
+		{name: "spec interval", interval: "5m", want: 5 * time.Minute},
+		{name: "component requests sooner", interval: "5m", requested: time.Minute, want: time.Minute},
+		{name: "component requests later", interval: "5m", requested: time.Hour, want: 5 * time.Minute},
+		{name: "absent", requested: time.Hour, want: time.Hour},
+		{name: "absent without requeue"},
+		{name: "invalid", interval: "often"},
+		{name: "negative", interval: "-1m"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := newTestObject("poll")
+			if tc.interval != "" {
+				obj.Annotations = map[string]string{key: tc.interval}
+			}
+			tr := newTestReconciler(t, obj)
+
+			r := tr.build("poll", func(r *Reconciler) {
+				r.WithSpecRequeueInterval(extract)
+				r.Component("poll", componentFunc(func(*Context) (ctrl.Result, error) {
+					return ctrl.Result{RequeueAfter: tc.requested}, nil
+				}))
+			})
+
+			res, err := tr.reconcile(r, client.ObjectKeyFromObject(obj))
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if res.RequeueAfter != tc.want {
+				t.Errorf("RequeueAfter = %v, want %v", res.RequeueAfter, tc.want)
+			}
+		})
+	}
+}
+
+func TestPostReconcileRequeue(t *testing.T) {
+	obj := newTestObject("post-requeue")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var first ctrl.Result
+	var secondErr error
+	var ran []string
+	r := tr.build("post-requeue", func(r *Reconciler) {
+		r.WithPostReconcileRequeue(time.Minute)
+		r.Component("first", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "first")
+			return first, nil
+		}))
+		r.Component("second", componentFunc(func(*Context) (ctrl.Result, error) {
+			ran = append(ran, "second")
+			return ctrl.Result{}, secondErr
+		}))
+	})
+
+	steps := []struct {
+		name    string
+		first   ctrl.Result
+		err     error
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "no component requeue", want: time.Minute},
+		{name: "shorter component requeue", first: ctrl.Result{RequeueAfter: 10 * time.Second}, want: 10 * time.Second},
+		{name: "longer component requeue", first: ctrl.Result{RequeueAfter: time.Hour}, want: time.Minute},
+		{name: "failed component", err: errors.New("boom"), wantErr: true},
+	}
+
+	for _, step := range steps {
+		first, secondErr, ran = step.first, step.err, nil
+		res, err := tr.reconcile(r, key)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%s: Reconcile() error = %v, want error %t", step.name, err, step.wantErr)
+		}
+		if len(ran) != 2 {
+			t.Errorf("%s: ran components %v, want all components to run", step.name, ran)
+		}
+		if res.RequeueAfter != step.want {
+			t.Errorf("%s: RequeueAfter = %v, want %v", step.name, res.RequeueAfter, step.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ReconcilerSet builds independent reconcilers for several api types managed by the same manager, applying shared
+// configuration such as components, metadata providers or log constructors to each of them.
+type ReconcilerSet struct {
+	mgr         ctrl.Manager
+	shared      []func(*Reconciler)
+	reconcilers []*Reconciler
+}
+
+func NewReconcilerSet(mgr ctrl.Manager) *ReconcilerSet {
+	return &ReconcilerSet{mgr: mgr}
+}
+
+// Configure registers fn to be applied to every reconciler of the set before its own configuration.
+func (s *ReconcilerSet) Configure(fn func(*Reconciler)) *ReconcilerSet {
+	s.shared = append(s.shared, fn)
+	return s
+}
+
+// For adds a reconciler for apiType, configured by the shared funcs followed by configure.
+func (s *ReconcilerSet) For(apiType client.Object, configure ...func(*Reconciler)) *ReconcilerSet {
+	r := NewReconciler(s.mgr).For(apiType)
+	for _, fn := range s.shared {
+		fn(r)
+	}
+	for _, fn := range configure {
+		fn(r)
+	}
+	s.reconcilers = append(s.reconcilers, r)
+
+	return s
+}
+
+// Build builds every reconciler of the set. Controllers are returned for all reconcilers that built successfully,
+// along with the aggregated errors of those that did not.
+func (s *ReconcilerSet) Build() ([]controller.Controller, error) {
+	var controllers []controller.Controller
+	var errs []error
+
+	for _, r := range s.reconcilers {
+		c, err := r.Build()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot build reconciler for %T: %w", r.apiType, err))
+			continue
+		}
+		controllers = append(controllers, c)
+	}
+
+	return controllers, utilerrors.NewAggregate(errs)
+}
+
+func (s *ReconcilerSet) Complete() error {
+	_, err := s.Build()
+	return err
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcilerSet(t *testing.T) {
+	obj := newTestObject("set")
+	health := &healthObject{ObjectMeta: metav1.ObjectMeta{Name: "set", Namespace: "default"}}
+	tr := newTestReconciler(t, obj, health)
+
+	var reconciled []string
+	set := NewReconcilerSet(tr.mgr).
+		Configure(func(r *Reconciler) {
+			r.client = tr.client
+			r.Component("shared", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+				reconciled = append(reconciled, fmt.Sprintf("%T/%s", ctx.Object, ctx.Object.GetName()))
+				return ctrl.Result{}, nil
+			}))
+		}).
+		For(&testObject{}, func(r *Reconciler) { r.Named("set-test") }).
+		For(&healthObject{}, func(r *Reconciler) { r.Named("set-health") })
+
+	controllers, err := set.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(controllers) != 2 {
+		t.Fatalf("Build() returned %d controllers, want one per type", len(controllers))
+	}
+
+	for _, r := range set.reconcilers {
+		r.recorder = newSafeRecorder(tr.events, tr.mgr.GetScheme(), r.log)
+		if _, err = tr.reconcile(r, client.ObjectKey{Namespace: "default", Name: "set"}); err != nil {
+			t.Fatalf("Reconcile() of %T error = %v", r.apiType, err)
+		}
+	}
+
+	want := []string{"*core.testObject/set", "*core.healthObject/set"}
+	if !reflect.DeepEqual(reconciled, want) {
+		t.Errorf("shared component reconciled %v, want %v", reconciled, want)
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SingletonConditionType is set by Context.EnforceSingleton on objects that support conditions.
+	SingletonConditionType = "Singleton"
+	// SingletonActiveReason indicates the object is the canonical instance.
+	SingletonActiveReason = "Active"
+	// SingletonDuplicateReason indicates another instance is canonical and the object is ignored.
+	SingletonDuplicateReason = "Duplicate"
+)
+
+// EnforceSingleton lists all instances of the reconcile object's type into list and reports whether the reconcile
+// object is the canonical one, i.e. the oldest instance with ties broken by namespace and name. The Singleton
+// condition is set accordingly; components should skip their work on non-canonical instances.
+func (c *Context) EnforceSingleton(list client.ObjectList) (bool, error) {
+	if err := c.Client.List(c, list); err != nil {
+		return false, fmt.Errorf("cannot list instances: %w", err)
+	}
+
+	var canonical client.Object = c.Object
+	err := meta.EachListItem(list, func(item runtime.Object) error {
+		obj, ok := item.(client.Object)
+		if ok && olderInstance(obj, canonical) {
+			canonical = obj
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if canonical.GetUID() != c.Object.GetUID() {
+		c.Conditions.SetFalse(SingletonConditionType, SingletonDuplicateReason,
+			fmt.Sprintf("Only one instance is allowed, %s is active", client.ObjectKeyFromObject(canonical)))
+		return false, nil
+	}
+
+	c.Conditions.SetTrue(SingletonConditionType, SingletonActiveReason, "Instance is active")
+	return true, nil
+}
+
+func olderInstance(a, b client.Object) bool {
+	aTime, bTime := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !aTime.Equal(&bTime) {
+		return aTime.Before(&bTime)
+	}
+	if a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	return a.GetName() < b.GetName()
+}
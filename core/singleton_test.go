@@ -0,0 +1,54 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestContextEnforceSingleton(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	first := newTestObject("first")
+	first.UID = "first-uid"
+	first.CreationTimestamp = created
+	second := newTestObject("second")
+	second.UID = "second-uid"
+	second.CreationTimestamp = metav1.NewTime(created.Add(time.Minute))
+	tr := newTestReconciler(t, first, second)
+
+	var active []string
+	r := tr.build("singleton", func(r *Reconciler) {
+		r.Component("singleton", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			canonical, err := ctx.EnforceSingleton(&testObjectList{})
+			if err != nil || !canonical {
+				return ctrl.Result{}, err
+			}
+			active = append(active, ctx.Object.GetName())
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for _, obj := range []*testObject{second, first} {
+		if _, err := tr.reconcile(r, client.ObjectKeyFromObject(obj)); err != nil {
+			t.Fatalf("Reconcile() of %s error = %v", obj.Name, err)
+		}
+	}
+
+	if want := []string{"first"}; !reflect.DeepEqual(active, want) {
+		t.Errorf("active instances = %v, want %v", active, want)
+	}
+	cond := FindStatusCondition(tr.get(client.ObjectKeyFromObject(first)).Status.Conditions, SingletonConditionType)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != SingletonActiveReason {
+		t.Errorf("%s condition of the oldest instance = %+v, want True/%s", SingletonConditionType, cond,
+			SingletonActiveReason)
+	}
+	cond = FindStatusCondition(tr.get(client.ObjectKeyFromObject(second)).Status.Conditions, SingletonConditionType)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != SingletonDuplicateReason {
+		t.Errorf("%s condition of the newer instance = %+v, want False/%s", SingletonConditionType, cond,
+			SingletonDuplicateReason)
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ReconcileStatus records the outcome of the last reconcile. Embed it into the status of an api type implementing
+// ReconcileStatusObject and enable it using Reconciler.WithLastReconcileStatus.
+type ReconcileStatus struct {
+	// LastReconcileTime is the time of the last reconcile that changed LastResult or LastError.
+	LastReconcileTime metav1.Time `json:"lastReconcileTime,omitempty"`
+	LastResult        string      `json:"lastResult,omitempty"`
+	LastError         string      `json:"lastError,omitempty"`
+}
+
+func (in *ReconcileStatus) DeepCopyInto(out *ReconcileStatus) {
+	*out = *in
+	in.LastReconcileTime.DeepCopyInto(&out.LastReconcileTime)
+}
+
+func (in *ReconcileStatus) DeepCopy() *ReconcileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// ReconcileStatusObject exposes the ReconcileStatus of an object.
+type ReconcileStatusObject interface {
+	GetReconcileStatus() *ReconcileStatus
+}
+
+// recordReconcileStatus stores the outcome of a reconcile on objects implementing ReconcileStatusObject. An outcome
+// equal to the recorded one is not written again, since every status change triggers another reconcile.
+func recordReconcileStatus(obj interface{}, res ctrl.Result, err error) {
+	statusObj, ok := obj.(ReconcileStatusObject)
+	if !ok {
+		return
+	}
+	status := statusObj.GetReconcileStatus()
+	if status == nil {
+		return
+	}
+
+	var result string
+	switch {
+	case res.RequeueAfter > 0:
+		result = fmt.Sprintf("RequeueAfter %s", res.RequeueAfter)
+	case res.Requeue:
+		result = "Requeue"
+	default:
+		result = "Done"
+	}
+	var lastError string
+	if err != nil {
+		lastError = err.Error()
+	}
+
+	if !status.LastReconcileTime.IsZero() && status.LastResult == result && status.LastError == lastError {
+		return
+	}
+	status.LastReconcileTime = metav1.NewTime(now())
+	status.LastResult = result
+	status.LastError = lastError
+}
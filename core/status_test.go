@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReconcileRecordsLastReconcileStatus(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	defer func(fn func() time.Time) { now = fn }(now)
+	now = func() time.Time { return clock }
+
+	obj := newTestObject("last-status")
+	obj.Status.Reconcile = &ReconcileStatus{}
+	// the fake client bumps the resourceVersion on every patch, hence writes are detected using the patch body
+	var patches []string
+	tr := newTestReconcilerWithFuncs(t, interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			patches = append(patches, string(data))
+			return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+		},
+	}, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var err error
+	r := tr.build("last-status", func(r *Reconciler) {
+		r.WithLastReconcileStatus()
+		r.Component("outcome", componentFunc(func(*Context) (ctrl.Result, error) {
+			return ctrl.Result{}, err
+		}))
+	})
+
+	steps := []struct {
+		name      string
+		err       error
+		wantError string
+		written   bool
+	}{
+		{name: "first success", written: true},
+		{name: "unchanged success"},
+		{name: "failure", err: errors.New("boom"), wantError: `component "outcome": boom`, written: true},
+		{name: "unchanged failure", err: errors.New("boom"), wantError: `component "outcome": boom`},
+		{name: "recovery", written: true},
+	}
+
+	for _, step := range steps {
+		clock = clock.Add(time.Minute)
+		err = step.err
+		before := tr.get(key)
+		patches = nil
+
+		_, _ = tr.reconcile(r, key)
+
+		stored := tr.get(key)
+		status := stored.Status.Reconcile
+		if status.LastResult != "Done" || status.LastError != step.wantError {
+			t.Errorf("%s: status = %+v, want result Done and error %q", step.name, status, step.wantError)
+		}
+		if written := len(patches) == 1 && strings.Contains(patches[0], `"reconcile"`); written != step.written {
+			t.Errorf("%s: status written = %t, want %t", step.name, written, step.written)
+		}
+		if step.written && !status.LastReconcileTime.Time.Equal(clock) {
+			t.Errorf("%s: LastReconcileTime = %v, want %v", step.name, status.LastReconcileTime, clock)
+		}
+		if !step.written && !status.LastReconcileTime.Equal(&before.Status.Reconcile.LastReconcileTime) {
+			t.Errorf("%s: LastReconcileTime changed without a new outcome", step.name)
+		}
+	}
+}
+
+// bareObject is an api type with neither status nor conditions.
+type bareObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func (o *bareObject) DeepCopyObject() runtime.Object {
+	c := *o
+	o.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return &c
+}
+
+type bareObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []bareObject `json:"items"`
+}
+
+func (l *bareObjectList) DeepCopyObject() runtime.Object {
+	c := *l
+	l.ListMeta.DeepCopyInto(&c.ListMeta)
+	c.Items = make([]bareObject, len(l.Items))
+	for i := range l.Items {
+		c.Items[i] = *l.Items[i].DeepCopyObject().(*bareObject)
+	}
+	return &c
+}
+
+// statusPatchCounter counts status patches sent through the fake client.
+func statusPatchCounter(n *int) interceptor.Funcs {
+	return interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			*n++
+			return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
+func TestReconcileTypeWithoutStatus(t *testing.T) {
+	obj := &bareObject{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"}}
+	statusPatches := 0
+	tr := newTestReconcilerWithFuncs(t, statusPatchCounter(&statusPatches), obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := NewReconciler(tr.mgr).For(&bareObject{}).Named("bare")
+	r.client = tr.client
+	r.Component("label", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+		ctx.Object.SetLabels(map[string]string{"example.com/labeled": "true"})
+		ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+		return ctrl.Result{}, nil
+	}))
+	if _, err := r.Build(); err != nil {
+		t.Fatalf("cannot build reconciler: %v", err)
+	}
+	if !r.statusDisabled {
+		t.Errorf("status patching not disabled for a type without status")
+	}
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if statusPatches != 0 {
+		t.Errorf("sent %d status patches, want none", statusPatches)
+	}
+	stored := &bareObject{}
+	if err := tr.client.Get(context.Background(), key, stored); err != nil {
+		t.Fatalf("cannot get object: %v", err)
+	}
+	if stored.Labels["example.com/labeled"] != "true" {
+		t.Errorf("labels = %v, want the label set by the component", stored.Labels)
+	}
+}
+
+func TestReconcileWithoutStatus(t *testing.T) {
+	obj := newTestObject("without-status")
+	statusPatches := 0
+	tr := newTestReconcilerWithFuncs(t, statusPatchCounter(&statusPatches), obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	r := tr.build("without-status", func(r *Reconciler) {
+		r.WithoutStatus()
+		r.Component("ready", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			ctx.Conditions.SetTrue("Ready", "Done", "component is ready")
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	if _, err := tr.reconcile(r, key); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if statusPatches != 0 {
+		t.Errorf("sent %d status patches, want none", statusPatches)
+	}
+	// conditions live in the status, hence they are not persisted either
+	if conditions := tr.get(key).Status.Conditions; len(conditions) != 0 {
+		t.Errorf("conditions = %+v, want none persisted", conditions)
+	}
+}
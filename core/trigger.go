@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TriggersContextDataKey is the ContextData key holding the []Trigger recorded for the current reconcile.
+const TriggersContextDataKey = "controller-util.dominodatalab.com/triggers"
+
+type TriggerEvent string
+
+const (
+	TriggerEventCreate  TriggerEvent = "Create"
+	TriggerEventUpdate  TriggerEvent = "Update"
+	TriggerEventDelete  TriggerEvent = "Delete"
+	TriggerEventGeneric TriggerEvent = "Generic"
+)
+
+// Trigger describes an owned object event that enqueued a reconcile of its owner.
+type Trigger struct {
+	GVK   schema.GroupVersionKind
+	Key   client.ObjectKey
+	Event TriggerEvent
+}
+
+// Triggers returns the owned object events that enqueued the current reconcile, if any were recorded.
+func (c *Context) Triggers() []Trigger {
+	triggers, _ := c.Data[TriggersContextDataKey].([]Trigger)
+	return triggers
+}
+
+type triggerStore struct {
+	mu       sync.Mutex
+	triggers map[types.NamespacedName][]Trigger
+}
+
+func newTriggerStore() *triggerStore {
+	return &triggerStore{triggers: map[types.NamespacedName][]Trigger{}}
+}
+
+func (s *triggerStore) add(owner types.NamespacedName, t Trigger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.triggers[owner] = append(s.triggers[owner], t)
+}
+
+func (s *triggerStore) pop(owner types.NamespacedName) []Trigger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	triggers := s.triggers[owner]
+	delete(s.triggers, owner)
+
+	return triggers
+}
+
+// triggerHandler enqueues the controller owner of an object and records the event that caused it.
+type triggerHandler struct {
+	r *Reconciler
+}
+
+func (h *triggerHandler) Create(_ context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, TriggerEventCreate, q)
+}
+
+func (h *triggerHandler) Update(_ context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.ObjectNew, TriggerEventUpdate, q)
+}
+
+func (h *triggerHandler) Delete(_ context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, TriggerEventDelete, q)
+}
+
+func (h *triggerHandler) Generic(_ context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(evt.Object, TriggerEventGeneric, q)
+}
+
+func (h *triggerHandler) enqueue(obj client.Object, evt TriggerEvent, q workqueue.RateLimitingInterface) {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return
+	}
+
+	ownerGV, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil || ownerGV.Group != h.r.resourceGVK.Group || owner.Kind != h.r.resourceGVK.Kind {
+		return
+	}
+
+	gvk, err := getGvk(obj, h.r.mgr.GetScheme())
+	if err != nil {
+		h.r.log.Error(err, "Cannot get GVK for triggering object", "object", client.ObjectKeyFromObject(obj))
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}}
+	h.r.triggers.add(req.NamespacedName, Trigger{GVK: gvk, Key: client.ObjectKeyFromObject(obj), Event: evt})
+	q.Add(req)
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileDrainsTriggersOnEarlyReturn(t *testing.T) {
+	skipped := newTestObject("skipped")
+	skipped.Annotations = map[string]string{SkipReconcileAnnotation: "true"}
+	tr := newTestReconciler(t, skipped)
+
+	r := tr.build("triggers", func(r *Reconciler) {
+		r.Component("noop", componentFunc(func(*Context) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	for _, key := range []client.ObjectKey{
+		client.ObjectKeyFromObject(skipped),
+		client.ObjectKeyFromObject(newTestObject("missing")),
+	} {
+		r.triggers.add(key, Trigger{Key: key, Event: TriggerEventUpdate})
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", key, err)
+		}
+		if triggers := r.triggers.pop(key); len(triggers) != 0 {
+			t.Errorf("triggers for %s left in store: %v", key, triggers)
+		}
+	}
+}
+
+func TestReconcileExposesTriggers(t *testing.T) {
+	obj := newTestObject("triggered")
+	tr := newTestReconciler(t, obj)
+	key := client.ObjectKeyFromObject(obj)
+
+	var seen [][]Trigger
+	r := tr.build("triggered", func(r *Reconciler) {
+		r.Component("record", componentFunc(func(ctx *Context) (ctrl.Result, error) {
+			seen = append(seen, ctx.Triggers())
+			return ctrl.Result{}, nil
+		}))
+	})
+
+	trigger := Trigger{Key: client.ObjectKey{Namespace: "default", Name: "child"}, Event: TriggerEventCreate}
+	r.triggers.add(key, trigger)
+	for i := 0; i < 2; i++ {
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	if len(seen) != 2 || len(seen[0]) != 1 || seen[0][0] != trigger || len(seen[1]) != 0 {
+		t.Errorf("triggers seen by reconciles = %v, want %v once", seen, trigger)
+	}
+}
+
+func TestTriggerHandlerEnqueuesControllerOnDelete(t *testing.T) {
+	tr := newTestReconciler(t)
+	r := tr.build("handler", func(*Reconciler) {})
+	h := &triggerHandler{r: r}
+
+	owner := newTestObject("owner")
+	owner.UID = "owner-uid"
+	other := newTestObject("other")
+	other.UID = "other-uid"
+	ownerRef := func(obj *testObject, controller bool) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion: testGroupVersion.String(),
+			Kind:       "testObject",
+			Name:       obj.Name,
+			UID:        obj.UID,
+			Controller: &controller,
+		}
+	}
+
+	owned := newTestObject("owned")
+	owned.OwnerReferences = []metav1.OwnerReference{ownerRef(other, false), ownerRef(owner, true)}
+	shared := newTestObject("shared")
+	shared.OwnerReferences = []metav1.OwnerReference{ownerRef(other, false)}
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h.Delete(context.Background(), event.DeleteEvent{Object: shared}, q)
+	h.Delete(context.Background(), event.DeleteEvent{Object: owned}, q)
+
+	if q.Len() != 1 {
+		t.Fatalf("queue length = %d, want only the controller owner", q.Len())
+	}
+	item, _ := q.Get()
+	want := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(owner)}
+	if item != want {
+		t.Errorf("enqueued %v, want %v", item, want)
+	}
+
+	triggers := r.triggers.pop(want.NamespacedName)
+	if len(triggers) != 1 || triggers[0].Key != client.ObjectKeyFromObject(owned) || triggers[0].Event != TriggerEventDelete {
+		t.Errorf("recorded triggers = %v, want the deletion of %s", triggers, client.ObjectKeyFromObject(owned))
+	}
+	if triggers := r.triggers.pop(client.ObjectKeyFromObject(other)); len(triggers) != 0 {
+		t.Errorf("triggers recorded for the non-controller owner: %v", triggers)
+	}
+}
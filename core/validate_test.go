@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestObjectValidator(t *testing.T) {
+	invalid := newTestObject("invalid")
+	invalid.Spec.Replicas = -1
+	deleting := newTestObject("invalid-deleting")
+	deleting.Spec.Replicas = -1
+	deleting.Finalizers = []string{"validator.test.dominodatalab.com/cleanup"}
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	valid := newTestObject("valid")
+	tr := newTestReconciler(t, invalid, deleting, valid)
+
+	var reconciled, finalized []string
+	validations := 0
+	r := tr.build("validator", func(r *Reconciler) {
+		r.WithObjectValidator(func(obj client.Object) error {
+			validations++
+			if obj.(*testObject).Spec.Replicas < 0 {
+				return errors.New("replicas must not be negative")
+			}
+			return nil
+		})
+		r.Component("cleanup", finalizerFunc{
+			componentFunc: func(ctx *Context) (ctrl.Result, error) {
+				reconciled = append(reconciled, ctx.Object.GetName())
+				return ctrl.Result{}, nil
+			},
+			finalize: func(ctx *Context) (ctrl.Result, bool, error) {
+				finalized = append(finalized, ctx.Object.GetName())
+				return ctrl.Result{}, true, nil
+			},
+		})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		key := client.ObjectKeyFromObject(invalid)
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if len(reconciled) != 0 {
+			t.Errorf("components ran for an invalid object: %v", reconciled)
+		}
+		cond := FindStatusCondition(tr.get(key).Status.Conditions, ValidConditionType)
+		if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "ValidationFailed" ||
+			cond.Message != "replicas must not be negative" {
+			t.Errorf("Valid condition = %+v, want False/ValidationFailed with the validation error", cond)
+		}
+		if events := tr.recordedEvents(); len(events) != 1 || !strings.HasPrefix(events[0], "Warning ValidationFailed") {
+			t.Errorf("events = %v, want a ValidationFailed warning", events)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		key := client.ObjectKeyFromObject(valid)
+		if _, err := tr.reconcile(r, key); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if len(reconciled) != 1 || reconciled[0] != "valid" {
+			t.Errorf("reconciled = %v, want the valid object", reconciled)
+		}
+		assertCondition(t, tr.get(key), ValidConditionType, metav1.ConditionTrue)
+	})
+
+	t.Run("deleting", func(t *testing.T) {
+		validations = 0
+		if _, err := tr.reconcile(r, client.ObjectKeyFromObject(deleting)); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if validations != 0 {
+			t.Errorf("validator ran %d times for an object pending deletion, want none", validations)
+		}
+		if len(finalized) != 1 || finalized[0] != "invalid-deleting" {
+			t.Errorf("finalized = %v, want the invalid object pending deletion", finalized)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// cacheWarmup is a manager runnable that starts informers for a set of kinds and reports ready once they have synced.
+type cacheWarmup struct {
+	cache  cache.Cache
+	gvks   []schema.GroupVersionKind
+	synced int32
+}
+
+func (w *cacheWarmup) Start(ctx context.Context) error {
+	for _, gvk := range w.gvks {
+		if _, err := w.cache.GetInformerForKind(ctx, gvk); err != nil {
+			return fmt.Errorf("cannot get informer for %s: %w", gvk, err)
+		}
+	}
+	if !w.cache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("cache did not sync")
+	}
+
+	atomic.StoreInt32(&w.synced, 1)
+	return nil
+}
+
+// NeedLeaderElection allows warmup on every replica since readiness is reported per process.
+func (w *cacheWarmup) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *cacheWarmup) Check(_ *http.Request) error {
+	if atomic.LoadInt32(&w.synced) == 0 {
+		return fmt.Errorf("informers for %v have not synced", w.gvks)
+	}
+	return nil
+}
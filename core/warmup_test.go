@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// syncingCache is a cache whose informers sync once synced is closed.
+type syncingCache struct {
+	cache.Cache
+	informerErr error
+	requested   []schema.GroupVersionKind
+	synced      chan struct{}
+}
+
+func (c *syncingCache) GetInformerForKind(
+	_ context.Context, gvk schema.GroupVersionKind, _ ...cache.InformerGetOption,
+) (cache.Informer, error) {
+	c.requested = append(c.requested, gvk)
+	return nil, c.informerErr
+}
+
+func (c *syncingCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func TestCacheWarmup(t *testing.T) {
+	gvk := testGroupVersion.WithKind("testObject")
+	c := &syncingCache{synced: make(chan struct{})}
+	warmup := &cacheWarmup{cache: c, gvks: []schema.GroupVersionKind{gvk}}
+
+	done := make(chan error)
+	go func() { done <- warmup.Start(context.Background()) }()
+
+	if err := warmup.Check(nil); err == nil {
+		t.Error("Check() reported ready before the informers synced")
+	}
+
+	close(c.synced)
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := warmup.Check(nil); err != nil {
+		t.Errorf("Check() error = %v after the informers synced", err)
+	}
+	if len(c.requested) != 1 || c.requested[0] != gvk {
+		t.Errorf("informers requested for %v, want %v", c.requested, gvk)
+	}
+}
+
+func TestCacheWarmupErrors(t *testing.T) {
+	gvks := []schema.GroupVersionKind{testGroupVersion.WithKind("testObject")}
+
+	c := &syncingCache{informerErr: errors.New("no matches for kind"), synced: make(chan struct{})}
+	err := (&cacheWarmup{cache: c, gvks: gvks}).Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no matches for kind") {
+		t.Errorf("Start() error = %v, want the informer error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	warmup := &cacheWarmup{cache: &syncingCache{synced: make(chan struct{})}, gvks: gvks}
+	if err = warmup.Start(ctx); err == nil {
+		t.Error("Start() succeeded although the cache never synced")
+	}
+	if err = warmup.Check(nil); err == nil {
+		t.Error("Check() reported ready although the cache never synced")
+	}
+}
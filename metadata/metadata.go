@@ -36,6 +36,7 @@ type Provider struct {
 
 	version       func(client.Object) string
 	dynamicLabels func(client.Object) map[string]string
+	instance      func(client.Object) string
 }
 
 type ProviderOpt func(p *Provider)
@@ -64,6 +65,14 @@ func WithDynamicLabels(fn func(client.Object) map[string]string) ProviderOpt {
 	}
 }
 
+// WithInstanceFunc derives the instance label value and instance name base from fn instead of the object name, e.g. to
+// keep them stable across renames.
+func WithInstanceFunc(fn func(client.Object) string) ProviderOpt {
+	return func(p *Provider) {
+		p.instance = fn
+	}
+}
+
 func NewProvider(name string, opts ...ProviderOpt) *Provider {
 	p := &Provider{application: name}
 	for _, opt := range opts {
@@ -73,18 +82,39 @@ func NewProvider(name string, opts ...ProviderOpt) *Provider {
 	return p
 }
 
+// Application returns the application name used for the name label.
+func (p *Provider) Application() string {
+	return p.application
+}
+
+// HasInstanceFunc reports whether the instance label value is derived using WithInstanceFunc rather than being the
+// object name.
+func (p *Provider) HasInstanceFunc() bool {
+	return p.instance != nil
+}
+
+// Clone returns a copy of the provider with opts applied, leaving the original unchanged.
+func (p *Provider) Clone(opts ...ProviderOpt) *Provider {
+	c := *p
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
 func (p *Provider) InstanceName(obj client.Object, ac AppComponent) string {
 	if ac == AppComponentNone {
-		return fmt.Sprintf("%s-%s", obj.GetName(), p.application)
+		return fmt.Sprintf("%s-%s", p.instanceOf(obj), p.application)
 	}
 
-	return fmt.Sprintf("%s-%s-%s", obj.GetName(), p.application, ac)
+	return fmt.Sprintf("%s-%s-%s", p.instanceOf(obj), p.application, ac)
 }
 
 func (p *Provider) StandardLabels(obj client.Object, ac AppComponent, extra map[string]string) map[string]string {
 	labels := map[string]string{
 		ApplicationNameLabelKey:     p.application,
-		ApplicationInstanceLabelKey: obj.GetName(),
+		ApplicationInstanceLabelKey: p.instanceOf(obj),
 	}
 
 	if p.creator != "" {
@@ -112,7 +142,7 @@ func (p *Provider) StandardLabels(obj client.Object, ac AppComponent, extra map[
 func (p *Provider) MatchLabels(obj client.Object, ac AppComponent) map[string]string {
 	labels := map[string]string{
 		ApplicationNameLabelKey:     p.application,
-		ApplicationInstanceLabelKey: obj.GetName(),
+		ApplicationInstanceLabelKey: p.instanceOf(obj),
 	}
 
 	if ac != AppComponentNone {
@@ -121,3 +151,10 @@ func (p *Provider) MatchLabels(obj client.Object, ac AppComponent) map[string]st
 
 	return labels
 }
+
+func (p *Provider) instanceOf(obj client.Object) string {
+	if p.instance != nil {
+		return p.instance(obj)
+	}
+	return obj.GetName()
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestProviderCloneIsIndependent(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "obj"}}
+	p := NewProvider("app", WithCreator("creator"))
+
+	c := p.Clone(WithCreator("override"), WithInstanceFunc(func(client.Object) string { return "fixed" }))
+	if got := c.StandardLabels(obj, AppComponentNone, nil); got[ApplicationCreatedByLabelKey] != "override" ||
+		got[ApplicationInstanceLabelKey] != "fixed" {
+		t.Errorf("clone labels = %v, want the overridden creator and instance", got)
+	}
+
+	got := p.StandardLabels(obj, AppComponentNone, nil)
+	if got[ApplicationCreatedByLabelKey] != "creator" || got[ApplicationInstanceLabelKey] != "obj" {
+		t.Errorf("original labels = %v, want the original creator and instance", got)
+	}
+	if p.HasInstanceFunc() {
+		t.Errorf("instance func of the clone leaked into the original")
+	}
+	if c.Application() != p.Application() {
+		t.Errorf("clone application = %q, want %q", c.Application(), p.Application())
+	}
+}
+
+func TestWithInstanceFunc(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "renamed", Labels: map[string]string{"id": "stable"}}}
+	p := NewProvider("app", WithInstanceFunc(func(obj client.Object) string { return obj.GetLabels()["id"] }))
+
+	if got := p.InstanceName(obj, "server"); got != "stable-app-server" {
+		t.Errorf("InstanceName() = %q, want stable-app-server", got)
+	}
+	if got := p.InstanceName(obj, AppComponentNone); got != "stable-app" {
+		t.Errorf("InstanceName() = %q, want stable-app", got)
+	}
+	if got := p.StandardLabels(obj, "server", nil)[ApplicationInstanceLabelKey]; got != "stable" {
+		t.Errorf("StandardLabels() instance = %q, want stable", got)
+	}
+	if got := p.MatchLabels(obj, "server")[ApplicationInstanceLabelKey]; got != "stable" {
+		t.Errorf("MatchLabels() instance = %q, want stable", got)
+	}
+	if !p.HasInstanceFunc() || NewProvider("app").HasInstanceFunc() {
+		t.Errorf("HasInstanceFunc() does not reflect the provider options")
+	}
+}
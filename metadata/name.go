@@ -0,0 +1,38 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxNameLength = 63
+	nameHashSize  = 10
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]`)
+
+// HashedName returns a deterministic DNS label made of prefix and a short hash of parts. The prefix is lowercased,
+// characters that are invalid in a DNS label are replaced with dashes and it is truncated so that the result never
+// exceeds 63 characters.
+func HashedName(prefix string, parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	hash := hex.EncodeToString(h.Sum(nil))[:nameHashSize]
+
+	prefix = invalidNameChars.ReplaceAllString(strings.ToLower(prefix), "-")
+	if len(prefix) > maxNameLength-nameHashSize-1 {
+		prefix = prefix[:maxNameLength-nameHashSize-1]
+	}
+	prefix = strings.Trim(prefix, "-")
+	if prefix == "" {
+		return hash
+	}
+
+	return prefix + "-" + hash
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+func TestHashedName(t *testing.T) {
+	name := HashedName("worker", "team", "owner")
+	if name != HashedName("worker", "team", "owner") {
+		t.Errorf("HashedName() is not deterministic")
+	}
+	if !strings.HasPrefix(name, "worker-") || len(name) != len("worker-")+nameHashSize {
+		t.Errorf("HashedName() = %q, want the prefix followed by a %d character hash", name, nameHashSize)
+	}
+	if name == HashedName("worker", "team", "other") {
+		t.Errorf("HashedName() does not depend on the parts")
+	}
+	if HashedName("worker", "ab", "c") == HashedName("worker", "a", "bc") {
+		t.Errorf("HashedName() does not separate the parts")
+	}
+
+	cases := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{name: "long prefix", prefix: strings.Repeat("a", 80), want: strings.Repeat("a", 52)},
+		{name: "dash at cut", prefix: strings.Repeat("a", 51) + "-b" + strings.Repeat("c", 20), want: strings.Repeat("a", 51)},
+		{name: "trailing dash", prefix: "worker-", want: "worker"},
+		{name: "empty prefix", prefix: "", want: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := HashedName(tc.prefix, "part")
+			if len(got) > 63 || !dnsLabel.MatchString(got) {
+				t.Errorf("HashedName() = %q, want a DNS label of at most 63 characters", got)
+			}
+			hash := got[len(got)-nameHashSize:]
+			want := hash
+			if tc.want != "" {
+				want = tc.want + "-" + hash
+			}
+			if got != want {
+				t.Errorf("HashedName() = %q, want %q", got, want)
+			}
+		})
+	}
+}